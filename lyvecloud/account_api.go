@@ -6,8 +6,12 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"log"
 	"net/http"
 	"strconv"
+	"strings"
+	"sync"
+	"time"
 )
 
 // ErrorResponse holds the parsed response in case of error.
@@ -27,6 +31,64 @@ type AuthRequest struct {
 type AuthData struct {
 	Token         string `json:"token"`
 	ExpirationSec string `json:"expirationSec"`
+
+	renewal *tokenRenewal // nil unless the token can be renewed.
+}
+
+// tokenRenewal renews the token of an AuthData, and of all its copies, before it expires.
+type tokenRenewal struct {
+	mu        sync.Mutex
+	token     string
+	expiresAt time.Time
+	renew     func() (*AuthData, error)
+}
+
+// tokenRenewalWindow is how long before its expiry a token is renewed.
+const tokenRenewalWindow = time.Minute
+
+// newTokenRenewal returns a renewal of the token of c, obtaining a new token with renew.
+func newTokenRenewal(c *AuthData, renew func() (*AuthData, error), now time.Time) *tokenRenewal {
+	r := &tokenRenewal{renew: renew}
+	r.set(c, now)
+	return r
+}
+
+func (r *tokenRenewal) set(c *AuthData, now time.Time) {
+	r.token = c.Token
+	r.expiresAt = time.Time{}
+	if seconds, err := strconv.Atoi(c.ExpirationSec); err == nil {
+		r.expiresAt = now.Add(time.Duration(seconds) * time.Second)
+	}
+}
+
+// current returns the token, renewed first if it is about to expire. If renewing fails, the old token is returned.
+func (r *tokenRenewal) current(now time.Time) string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.expiresAt.IsZero() || now.Add(tokenRenewalWindow).Before(r.expiresAt) {
+		return r.token
+	}
+
+	log.Printf("[DEBUG] Renewing Account API token expiring at %s", r.expiresAt)
+
+	c, err := r.renew()
+	if err != nil {
+		log.Printf("[WARN] Unable to renew Account API token: %s", err)
+		return r.token
+	}
+
+	r.set(c, now)
+
+	return r.token
+}
+
+// bearerToken returns the token to authorize requests with.
+func (c *AuthData) bearerToken() string {
+	if c.renewal != nil {
+		return c.renewal.current(time.Now())
+	}
+	return c.Token
 }
 
 // Permission specifies parameters for CreatePermission and UpdatePermission.
@@ -82,12 +144,42 @@ type GetServiceAccountResponse struct {
 	Permissions []string `json:"permissions"`
 }
 
+// BucketUsage holds the storage usage of a single bucket.
+type BucketUsage struct {
+	Name    string  `json:"name"`
+	UsageGB float64 `json:"usageGB"`
+}
+
+// Usage holds the storage usage of the account.
+type Usage struct {
+	TotalUsageGB  float64       `json:"totalUsageGB"`
+	NumBuckets    int           `json:"numBuckets"`
+	UsageByBucket []BucketUsage `json:"usageByBucket"`
+}
+
+// CurrentUsageResponse holds the parsed response from GetCurrentUsage.
+type CurrentUsageResponse struct {
+	Usage Usage `json:"usage"`
+}
+
 // The Dates structure holds the dates for which usage should be retrieved.
 type Dates struct {
-	fromMonth int
-	fromYear  int
-	toMonth   int
-	toYear    int
+	FromMonth int
+	FromYear  int
+	ToMonth   int
+	ToYear    int
+}
+
+// MonthlyUsage holds the storage usage of the account for a single month.
+type MonthlyUsage struct {
+	Year  int `json:"year"`
+	Month int `json:"month"`
+	Usage
+}
+
+// MonthlyUsageResponse holds the parsed response from GetUsageByDate.
+type MonthlyUsageResponse struct {
+	Usage []MonthlyUsage `json:"usage"`
 }
 
 // AuthAccountAPI returns access token.
@@ -161,6 +253,27 @@ func (c *AuthData) GetPermission(permissionId string) (*GetPermissionResponse, e
 	return getPermissionResp, nil
 }
 
+// ListPermissions retrieves all permissions.
+func (c *AuthData) ListPermissions() ([]GetPermissionResponse, error) {
+	resp, err := CreateAndSendRequest(http.MethodGet, PermissionUrl, HeadersGet(c), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	listPermissionsResp := []GetPermissionResponse{}
+	if err = json.Unmarshal(resBody, &listPermissionsResp); err != nil {
+		return nil, err
+	}
+
+	return listPermissionsResp, nil
+}
+
 // DeletePermission deletes permission.
 func (c *AuthData) DeletePermission(permissionId string) (int, error) {
 	resp, err := CreateAndSendRequest(http.MethodDelete, PermissionUrl+SlashSeparator+permissionId, HeadersDelete(c), nil)
@@ -232,6 +345,27 @@ func (c *AuthData) GetServiceAccount(serviceAccountId string) (*GetServiceAccoun
 	return getServiceAccountResp, nil
 }
 
+// ListServiceAccounts retrieves all service accounts.
+func (c *AuthData) ListServiceAccounts() ([]GetServiceAccountResponse, error) {
+	resp, err := CreateAndSendRequest(http.MethodGet, SAUrl, HeadersGet(c), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	listServiceAccountsResp := []GetServiceAccountResponse{}
+	if err = json.Unmarshal(resBody, &listServiceAccountsResp); err != nil {
+		return nil, err
+	}
+
+	return listServiceAccountsResp, nil
+}
+
 // UpdateServiceAccount updates given service account.
 func (c *AuthData) UpdateServiceAccount(serviceAccountId string, serviceAccount *ServiceAccount) (int, error) {
 	payload, err := json.Marshal(serviceAccount)
@@ -277,43 +411,48 @@ func (c *AuthData) DeleteServiceAccount(serviceAccountId string) (int, error) {
 	return resp.StatusCode, nil
 }
 
-// GetUsageByDate returns the historical storage usage by month
-func (c *AuthData) GetUsageByDate(dates Dates) (string, error) {
+// GetUsageByDate returns the historical storage usage by month.
+func (c *AuthData) GetUsageByDate(dates Dates) (*MonthlyUsageResponse, error) {
 	// parse Dates struct to query string
 	datesQuery := generateQueryString(dates)
 	resp, err := CreateAndSendRequest(http.MethodGet, UsageMonthlyUrl+datesQuery, HeadersGet(c), nil)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
 	resBody, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 	defer resp.Body.Close()
 
-	jsonResponse := string(resBody)
+	var monthlyUsageResp *MonthlyUsageResponse
+	if err = json.Unmarshal(resBody, &monthlyUsageResp); err != nil {
+		return nil, err
+	}
 
-	return jsonResponse, nil
+	return monthlyUsageResp, nil
 }
 
-// GetCurrentUsage returns the current month's storage usage in JSON string
-func (c *AuthData) GetCurrentUsage() (string, error) {
-	// parse Dates struct to string
+// GetCurrentUsage returns the current month's storage usage.
+func (c *AuthData) GetCurrentUsage() (*CurrentUsageResponse, error) {
 	resp, err := CreateAndSendRequest(http.MethodGet, UsageCurrentUrl, HeadersGet(c), nil)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
 	resBody, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 	defer resp.Body.Close()
 
-	jsonResponse := string(resBody)
+	var currentUsageResp *CurrentUsageResponse
+	if err = json.Unmarshal(resBody, &currentUsageResp); err != nil {
+		return nil, err
+	}
 
-	return jsonResponse, nil
+	return currentUsageResp, nil
 }
 
 // CreateAndSendRequest creates http request and sends it.
@@ -324,11 +463,23 @@ func CreateAndSendRequest(method, url string, headers map[string][]string, body
 	}
 	req.Header = headers
 
+	// an expired token fails before the request is sent, rather than with a bare 401 of the Account API.
+	if token := strings.TrimPrefix(req.Header.Get(Authorization), Bearer); token != "" {
+		if expiresAt, ok := accountTokenExpiration(token); ok && !time.Now().Before(expiresAt) {
+			return nil, fmt.Errorf("Account API token expired at %s, obtain a new token", expiresAt.UTC().Format(time.RFC3339))
+		}
+	}
+
 	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
 		return nil, err
 	}
 
+	if resp.StatusCode == http.StatusUnauthorized {
+		resp.Body.Close()
+		return nil, fmt.Errorf("Account API token expired or invalid: the Account API responded %s, obtain a new token or check the account credentials", resp.Status)
+	}
+
 	if resp.StatusCode != http.StatusOK {
 		resBody, err := io.ReadAll(resp.Body)
 		if err != nil {
@@ -354,7 +505,7 @@ func CreateAndSendRequest(method, url string, headers map[string][]string, body
 // generateQueryString takes a Dates struct and generates a query string based on its fields.
 func generateQueryString(dates Dates) string {
 	return fmt.Sprintf("?fromMonth=%d&fromYear=%d&toMonth=%d&toYear=%d",
-		dates.fromMonth, dates.fromYear, dates.toMonth, dates.toYear)
+		dates.FromMonth, dates.FromYear, dates.ToMonth, dates.ToYear)
 }
 
 // HeadersAuth returns headers for authorization.
@@ -370,7 +521,7 @@ func HeadersAuth() map[string][]string {
 func HeadersGet(c *AuthData) map[string][]string {
 	return map[string][]string{
 		Accept:        {Json},
-		Authorization: {Bearer + c.Token},
+		Authorization: {Bearer + c.bearerToken()},
 		UserAgent:     {TerraformProvider},
 	}
 }
@@ -379,7 +530,7 @@ func HeadersGet(c *AuthData) map[string][]string {
 func HeadersDelete(c *AuthData) map[string][]string {
 	return map[string][]string{
 		Accept:        {Json},
-		Authorization: {Bearer + c.Token},
+		Authorization: {Bearer + c.bearerToken()},
 		UserAgent:     {TerraformProvider},
 	}
 }
@@ -387,7 +538,7 @@ func HeadersDelete(c *AuthData) map[string][]string {
 // HeadersDelete returns headers for creating permission/service account.
 func HeadersCreate(c *AuthData) map[string][]string {
 	return map[string][]string{
-		Authorization: {Bearer + c.Token},
+		Authorization: {Bearer + c.bearerToken()},
 		ContentType:   {Json},
 		Accept:        {Json},
 		UserAgent:     {TerraformProvider},
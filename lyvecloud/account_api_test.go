@@ -0,0 +1,221 @@
+package lyvecloud
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeAccountAPI replaces the transport of http.DefaultClient for the duration of a test, serving Account API
+// requests with the handlers registered by method and path, e.g. "GET /v2/permissions".
+type fakeAccountAPI struct {
+	mu       sync.Mutex
+	handlers map[string]http.HandlerFunc
+	requests []string
+}
+
+func newFakeAccountAPI(t *testing.T, handlers map[string]http.HandlerFunc) *fakeAccountAPI {
+	f := &fakeAccountAPI{handlers: handlers}
+
+	transport := http.DefaultClient.Transport
+	http.DefaultClient.Transport = f
+	t.Cleanup(func() {
+		http.DefaultClient.Transport = transport
+	})
+
+	return f
+}
+
+func (f *fakeAccountAPI) RoundTrip(r *http.Request) (*http.Response, error) {
+	key := r.Method + " " + r.URL.Path
+
+	f.mu.Lock()
+	f.requests = append(f.requests, key)
+	handler, ok := f.handlers[key]
+	f.mu.Unlock()
+
+	if !ok {
+		handler = jsonHandler(http.StatusNotFound, map[string]string{"code": "NotFound"})
+	}
+
+	recorder := httptest.NewRecorder()
+	handler(recorder, r)
+
+	resp := recorder.Result()
+	resp.Request = r
+
+	return resp, nil
+}
+
+// count returns the number of requests made with the given method and path.
+func (f *fakeAccountAPI) count(key string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	n := 0
+	for _, request := range f.requests {
+		if request == key {
+			n++
+		}
+	}
+	return n
+}
+
+// jsonHandler responds with status and body encoded as JSON.
+func jsonHandler(status int, body interface{}) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+		json.NewEncoder(w).Encode(body)
+	}
+}
+
+func TestTokenRenewal(t *testing.T) {
+	now := time.Date(2023, time.March, 31, 12, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		Name          string
+		ExpirationSec string
+		At            time.Duration
+		RenewErr      error
+		Expected      string
+		Renewed       bool
+	}{
+		{
+			Name:          "valid token",
+			ExpirationSec: "3600",
+			At:            30 * time.Minute,
+			Expected:      "old",
+		},
+		{
+			Name:          "token about to expire",
+			ExpirationSec: "3600",
+			At:            time.Hour - tokenRenewalWindow,
+			Expected:      "new",
+			Renewed:       true,
+		},
+		{
+			Name:          "renewal failure",
+			ExpirationSec: "3600",
+			At:            2 * time.Hour,
+			RenewErr:      errors.New("credential_process failed"),
+			Expected:      "old",
+			Renewed:       true,
+		},
+		{
+			Name:     "unknown expiry",
+			At:       48 * time.Hour,
+			Expected: "old",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			renewed := false
+			renewal := newTokenRenewal(&AuthData{Token: "old", ExpirationSec: testCase.ExpirationSec}, func() (*AuthData, error) {
+				renewed = true
+				if testCase.RenewErr != nil {
+					return nil, testCase.RenewErr
+				}
+				return &AuthData{Token: "new", ExpirationSec: "3600"}, nil
+			}, now)
+
+			if got := renewal.current(now.Add(testCase.At)); got != testCase.Expected {
+				t.Errorf("got token %q, expected %q", got, testCase.Expected)
+			}
+
+			if renewed != testCase.Renewed {
+				t.Errorf("got renewed %t, expected %t", renewed, testCase.Renewed)
+			}
+		})
+	}
+}
+
+func TestListPermissions(t *testing.T) {
+	api := newFakeAccountAPI(t, map[string]http.HandlerFunc{
+		"GET /v2/permissions": jsonHandler(http.StatusOK, []GetPermissionResponse{{Id: "p1"}, {Id: "p2"}}),
+	})
+
+	conn := &AuthData{}
+	permissions, err := conn.ListPermissions()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	ids := []string{}
+	for _, permission := range permissions {
+		ids = append(ids, permission.Id)
+	}
+
+	if expected := []string{"p1", "p2"}; !reflect.DeepEqual(ids, expected) {
+		t.Errorf("got %v, expected %v", ids, expected)
+	}
+
+	if got := api.count("GET /v2/permissions"); got != 1 {
+		t.Errorf("got %d requests, expected 1", got)
+	}
+}
+
+func TestCreateAndSendRequestToken(t *testing.T) {
+	jwt := func(expiresAt time.Time) string {
+		encode := base64.RawURLEncoding.EncodeToString
+		return encode([]byte(`{"alg":"HS256","typ":"JWT"}`)) + "." + encode([]byte(fmt.Sprintf(`{"exp":%d}`, expiresAt.Unix()))) + ".signature"
+	}
+
+	testCases := []struct {
+		Name             string
+		Token            string
+		Status           int
+		ExpectedError    string
+		ExpectedRequests int
+	}{
+		{
+			Name:             "valid",
+			Token:            jwt(time.Now().Add(time.Hour)),
+			Status:           http.StatusOK,
+			ExpectedRequests: 1,
+		},
+		{
+			Name:          "expired",
+			Token:         jwt(time.Now().Add(-time.Minute)),
+			Status:        http.StatusOK,
+			ExpectedError: "Account API token expired at",
+		},
+		{
+			Name:             "unauthorized",
+			Token:            "opaque-token",
+			Status:           http.StatusUnauthorized,
+			ExpectedError:    "Account API token expired or invalid: the Account API responded 401 Unauthorized",
+			ExpectedRequests: 1,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			api := newFakeAccountAPI(t, map[string]http.HandlerFunc{
+				"GET /v2/permissions/permission-id": func(w http.ResponseWriter, r *http.Request) {
+					w.WriteHeader(testCase.Status)
+				},
+			})
+
+			_, err := CreateAndSendRequest(http.MethodGet, PermissionUrl+SlashSeparator+"permission-id", HeadersGet(&AuthData{Token: testCase.Token}), nil)
+			if testCase.ExpectedError == "" && err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if testCase.ExpectedError != "" && (err == nil || !strings.Contains(err.Error(), testCase.ExpectedError)) {
+				t.Fatalf("got error %v, expected it to contain %q", err, testCase.ExpectedError)
+			}
+
+			if got := len(api.requests); got != testCase.ExpectedRequests {
+				t.Errorf("got %d requests, expected %d", got, testCase.ExpectedRequests)
+			}
+		})
+	}
+}
@@ -0,0 +1,103 @@
+package lyvecloud
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// bucketPattern is one of the allowed_bucket_patterns of the provider, matching whole bucket names.
+type bucketPattern struct {
+	pattern string
+	re      *regexp.Regexp
+}
+
+// expandBucketPatterns compiles the allowed_bucket_patterns of the provider. Patterns enclosed in slashes are
+// regular expressions, others are globs where * matches any sequence of characters and ? any single character.
+func expandBucketPatterns(patterns []string) ([]bucketPattern, error) {
+	bucketPatterns := []bucketPattern{}
+	for _, pattern := range patterns {
+		var expr string
+		if len(pattern) > 2 && strings.HasPrefix(pattern, "/") && strings.HasSuffix(pattern, "/") {
+			expr = pattern[1 : len(pattern)-1]
+		} else {
+			expr = strings.NewReplacer(`\*`, ".*", `\?`, ".").Replace(regexp.QuoteMeta(pattern))
+		}
+
+		re, err := regexp.Compile("^(?:" + expr + ")$")
+		if err != nil {
+			return nil, fmt.Errorf("error parsing allowed_bucket_patterns pattern (%s): %w", pattern, err)
+		}
+
+		bucketPatterns = append(bucketPatterns, bucketPattern{pattern: pattern, re: re})
+	}
+	return bucketPatterns, nil
+}
+
+// CheckAllowedBucket returns an error if the provider restricts buckets with allowed_bucket_patterns and none matches bucket.
+func CheckAllowedBucket(client Client, bucket string) error {
+	if client.AllowedBucketPatterns == nil {
+		return nil
+	}
+
+	patterns := []string{}
+	for _, pattern := range client.AllowedBucketPatterns {
+		if pattern.re.MatchString(bucket) {
+			return nil
+		}
+		patterns = append(patterns, pattern.pattern)
+	}
+
+	return fmt.Errorf("bucket (%s) is outside the allowed_bucket_patterns of the provider: %s", bucket, strings.Join(patterns, ", "))
+}
+
+// customizeDiffAllowedBucket checks the bucket of a resource against the allowed_bucket_patterns of the provider at plan time.
+// Unknown buckets are checked when they are known, at the latest when the plan is applied.
+func customizeDiffAllowedBucket(_ context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	client, ok := meta.(Client)
+	if !ok || !d.NewValueKnown("bucket") {
+		return nil
+	}
+
+	bucket := d.Get("bucket").(string)
+	if bucket == "" {
+		return nil
+	}
+
+	return CheckAllowedBucket(client, bucket)
+}
+
+// customizeDiffAllowedCopySource checks the bucket of the source of an object copy against the allowed_bucket_patterns
+// of the provider at plan time, as the copy reads from it.
+func customizeDiffAllowedCopySource(_ context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	client, ok := meta.(Client)
+	if !ok || !d.NewValueKnown("source") {
+		return nil
+	}
+
+	source := d.Get("source").(string)
+	if source == "" {
+		return nil
+	}
+
+	return CheckAllowedBucket(client, copySourceBucket(source))
+}
+
+// copySourceBucket returns the bucket of the source of an object copy, in the format [/]bucket/key.
+func copySourceBucket(source string) string {
+	bucket, _, _ := strings.Cut(strings.TrimPrefix(source, "/"), "/")
+	return bucket
+}
+
+// importStateAllowedBucket imports resources whose ID is their bucket, which must be allowed by the
+// allowed_bucket_patterns of the provider, as CustomizeDiff doesn't run on import.
+func importStateAllowedBucket(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	if err := CheckAllowedBucket(meta.(Client), d.Id()); err != nil {
+		return nil, err
+	}
+
+	return schema.ImportStatePassthroughContext(ctx, d, meta)
+}
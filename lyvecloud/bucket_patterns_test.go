@@ -0,0 +1,231 @@
+package lyvecloud
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestCheckAllowedBucket(t *testing.T) {
+	testCases := []struct {
+		Name        string
+		Patterns    []string
+		Bucket      string
+		ExpectError bool
+	}{
+		{
+			Name:   "no patterns",
+			Bucket: "any-bucket",
+		},
+		{
+			Name:     "glob match",
+			Patterns: []string{"team-a-*"},
+			Bucket:   "team-a-logs",
+		},
+		{
+			Name:        "glob mismatch",
+			Patterns:    []string{"team-a-*"},
+			Bucket:      "team-b-logs",
+			ExpectError: true,
+		},
+		{
+			Name:        "glob matches whole name",
+			Patterns:    []string{"team-a-?"},
+			Bucket:      "team-a-logs",
+			ExpectError: true,
+		},
+		{
+			Name:     "glob with regular expression characters",
+			Patterns: []string{"team.a-*"},
+			Bucket:   "team.a-logs",
+		},
+		{
+			Name:        "glob dot is not a wildcard",
+			Patterns:    []string{"team.a-*"},
+			Bucket:      "teamxa-logs",
+			ExpectError: true,
+		},
+		{
+			Name:     "regular expression match",
+			Patterns: []string{"/team-(a|b)-[0-9]+/"},
+			Bucket:   "team-b-42",
+		},
+		{
+			Name:        "regular expression matches whole name",
+			Patterns:    []string{"/team-(a|b)-[0-9]+/"},
+			Bucket:      "team-b-42-logs",
+			ExpectError: true,
+		},
+		{
+			Name:     "any pattern match",
+			Patterns: []string{"team-a-*", "shared-*"},
+			Bucket:   "shared-artifacts",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			var client Client
+			if testCase.Patterns != nil {
+				patterns, err := expandBucketPatterns(testCase.Patterns)
+				if err != nil {
+					t.Fatalf("unexpected error: %s", err)
+				}
+				client.AllowedBucketPatterns = patterns
+			}
+
+			err := CheckAllowedBucket(client, testCase.Bucket)
+
+			if testCase.ExpectError && err == nil {
+				t.Error("expected error")
+			}
+
+			if !testCase.ExpectError && err != nil {
+				t.Errorf("unexpected error: %s", err)
+			}
+		})
+	}
+}
+
+func TestExpandBucketPatternsInvalid(t *testing.T) {
+	if _, err := expandBucketPatterns([]string{"/team-(a/"}); err == nil {
+		t.Error("expected error")
+	}
+}
+
+// testAllowedBucketClient returns a client allowing team-a-* buckets, whose S3 client can't make any API call,
+// which would panic.
+func testAllowedBucketClient(t *testing.T) Client {
+	patterns, err := expandBucketPatterns([]string{"team-a-*"})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	return Client{S3Client: &s3.S3{}, AllowedBucketPatterns: patterns}
+}
+
+func TestAllowedBucketDeleteAndImport(t *testing.T) {
+	testCases := []struct {
+		Name     string
+		Resource *schema.Resource
+		Raw      map[string]interface{}
+		Id       string
+		ImportId string
+	}{
+		{
+			Name:     "bucket",
+			Resource: ResourceBucket(),
+			Raw:      map[string]interface{}{"bucket": "team-b-logs"},
+			Id:       "team-b-logs",
+			ImportId: "team-b-logs",
+		},
+		{
+			Name:     "object",
+			Resource: ResourceObject(),
+			Raw:      map[string]interface{}{"bucket": "team-b-logs", "key": "key"},
+			Id:       "key",
+			ImportId: "team-b-logs/key",
+		},
+		{
+			Name:     "object copy",
+			Resource: ResourceObjectCopy(),
+			Raw:      map[string]interface{}{"bucket": "team-b-logs", "key": "key", "source": "team-a-logs/key"},
+			Id:       "key",
+		},
+		{
+			Name:     "object lock configuration",
+			Resource: ResourceBucketObjectLockConfiguration(),
+			Raw:      map[string]interface{}{"bucket": "team-b-logs"},
+			Id:       "team-b-logs",
+			ImportId: "team-b-logs",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			meta := testAllowedBucketClient(t)
+			r := testCase.Resource
+
+			d := schema.TestResourceDataRaw(t, r.Schema, testCase.Raw)
+			d.SetId(testCase.Id)
+
+			var errs []error
+			if r.Delete != nil {
+				errs = append(errs, r.Delete(d, meta))
+			}
+			if r.DeleteContext != nil {
+				errs = append(errs, diagError(r.DeleteContext(context.Background(), d, meta)))
+			}
+
+			if testCase.ImportId != "" {
+				d := r.TestResourceData()
+				d.SetId(testCase.ImportId)
+
+				var err error
+				if r.Importer.State != nil {
+					_, err = r.Importer.State(d, meta)
+				} else {
+					_, err = r.Importer.StateContext(context.Background(), d, meta)
+				}
+				errs = append(errs, err)
+			}
+
+			for _, err := range errs {
+				if err == nil || !strings.Contains(err.Error(), "outside the allowed_bucket_patterns") {
+					t.Errorf("got error %v, expected the bucket to be outside the allowed_bucket_patterns", err)
+				}
+			}
+		})
+	}
+}
+
+func TestAllowedBucketObjectCopySource(t *testing.T) {
+	testCases := []struct {
+		Name        string
+		Source      string
+		ExpectError bool
+	}{
+		{
+			Name:   "allowed source",
+			Source: "team-a-logs/key",
+		},
+		{
+			Name:   "allowed source with leading slash",
+			Source: "/team-a-logs/key",
+		},
+		{
+			Name:        "source outside the patterns",
+			Source:      "team-b-logs/key",
+			ExpectError: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			meta := testAllowedBucketClient(t)
+			raw := map[string]interface{}{"bucket": "team-a-copies", "key": "key", "source": testCase.Source}
+
+			_, err := ResourceObjectCopy().Diff(context.Background(), nil, terraform.NewResourceConfigRaw(raw), meta)
+
+			if testCase.ExpectError && (err == nil || !strings.Contains(err.Error(), "bucket (team-b-logs) is outside")) {
+				t.Errorf("got error %v, expected the source bucket to be outside the allowed_bucket_patterns", err)
+			}
+
+			if !testCase.ExpectError && err != nil {
+				t.Errorf("unexpected error: %s", err)
+			}
+
+			// the source is checked again when copying, it may be unknown at plan time.
+			if testCase.ExpectError {
+				d := schema.TestResourceDataRaw(t, ResourceObjectCopy().Schema, raw)
+				if err := resourceObjectCopyCreate(d, meta); err == nil || !strings.Contains(err.Error(), "bucket (team-b-logs) is outside") {
+					t.Errorf("got error %v, expected the source bucket to be outside the allowed_bucket_patterns", err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,112 @@
+package lyvecloud
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func DataSourcePermission() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourcePermissionRead,
+		Schema: map[string]*schema.Schema{
+			"id": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ExactlyOneOf: []string{"id", "name"},
+			},
+			"name": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ExactlyOneOf: []string{"id", "name"},
+			},
+			"description": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"type": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"actions": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"buckets": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"bucket_prefix": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"policy": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"ready_state": {
+				Type:     schema.TypeBool,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func dataSourcePermissionRead(d *schema.ResourceData, meta interface{}) error {
+	if CheckCredentials(AccountAPI, meta.(Client)) {
+		return fmt.Errorf("credentials for account api are missing")
+	}
+
+	conn := *meta.(Client).AccountAPIClient
+
+	permissionId := d.Get("id").(string)
+	if name, ok := d.GetOk("name"); ok {
+		permission, err := findPermissionByName(&conn, name.(string))
+		if err != nil {
+			return err
+		}
+		permissionId = permission.Id
+	}
+
+	resp, err := conn.GetPermission(permissionId)
+	if err != nil {
+		return fmt.Errorf("error reading permission (%s): %w", permissionId, err)
+	}
+
+	d.SetId(resp.Id)
+	d.Set("name", resp.Name)
+	d.Set("description", resp.Description)
+	d.Set("type", resp.Type)
+	d.Set("ready_state", resp.ReadyState)
+
+	if resp.Type != "policy" {
+		d.Set("actions", resp.Actions)
+	}
+
+	if resp.Type == "bucket-names" {
+		d.Set("buckets", resp.Buckets)
+	}
+
+	if resp.Type == "bucket-prefix" {
+		d.Set("bucket_prefix", resp.Prefix)
+	}
+
+	policy, err := unescape(resp.Policy)
+	if err != nil {
+		return fmt.Errorf("Error parsing policy: %s", err)
+	}
+
+	policy, err = NormalizeJsonString(policy)
+	if err != nil {
+		return fmt.Errorf("policy (%s) is invalid JSON: %w", policy, err)
+	}
+
+	d.Set("policy", policy)
+
+	return nil
+}
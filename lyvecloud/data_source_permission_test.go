@@ -0,0 +1,98 @@
+package lyvecloud
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestDataSourcePermissionRead(t *testing.T) {
+	testCases := []struct {
+		Name          string
+		Raw           map[string]interface{}
+		ExpectedId    string
+		ExpectedError string
+	}{
+		{
+			Name:       "by id",
+			Raw:        map[string]interface{}{"id": "p-1"},
+			ExpectedId: "p-1",
+		},
+		{
+			Name:       "by name",
+			Raw:        map[string]interface{}{"name": "logs"},
+			ExpectedId: "p-1",
+		},
+		{
+			Name:          "id not found",
+			Raw:           map[string]interface{}{"id": "p-missing"},
+			ExpectedError: "error reading permission (p-missing)",
+		},
+		{
+			Name:          "name not found",
+			Raw:           map[string]interface{}{"name": "missing"},
+			ExpectedError: "permission (missing) not found",
+		},
+		{
+			Name:          "ambiguous name",
+			Raw:           map[string]interface{}{"name": "shared"},
+			ExpectedError: "multiple permissions named shared: p-2, p-3",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			newFakeAccountAPI(t, map[string]http.HandlerFunc{
+				"GET /v2/permissions": jsonHandler(http.StatusOK, []GetPermissionResponse{
+					{Id: "p-1", Name: "logs"},
+					{Id: "p-2", Name: "shared"},
+					{Id: "p-3", Name: "shared"},
+				}),
+				"GET /v2/permissions/p-1": jsonHandler(http.StatusOK, GetPermissionResponse{
+					Id:          "p-1",
+					Name:        "logs",
+					Description: "logs writers",
+					Type:        "bucket-names",
+					Actions:     "write-only",
+					Buckets:     []string{"logs"},
+					ReadyState:  true,
+				}),
+			})
+
+			d := schema.TestResourceDataRaw(t, DataSourcePermission().Schema, testCase.Raw)
+			err := dataSourcePermissionRead(d, Client{AccountAPIClient: &AuthData{}})
+			if testCase.ExpectedError != "" {
+				if err == nil || !strings.Contains(err.Error(), testCase.ExpectedError) {
+					t.Fatalf("got error %v, expected it to contain %q", err, testCase.ExpectedError)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if d.Id() != testCase.ExpectedId {
+				t.Errorf("got id %q, expected %q", d.Id(), testCase.ExpectedId)
+			}
+
+			expected := map[string]interface{}{
+				"name":          "logs",
+				"description":   "logs writers",
+				"type":          "bucket-names",
+				"actions":       "write-only",
+				"buckets.#":     1,
+				"buckets.0":     "logs",
+				"bucket_prefix": "",
+				"ready_state":   true,
+			}
+
+			for key, value := range expected {
+				if got := d.Get(key); got != value {
+					t.Errorf("got %s %v, expected %v", key, got, value)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,238 @@
+package lyvecloud
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func DataSourcePermissions() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourcePermissionsRead,
+		Schema: map[string]*schema.Schema{
+			"name_regex": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringIsValidRegExp,
+			},
+			"type": {
+				Type:     schema.TypeString,
+				Optional: true,
+				ValidateFunc: validation.StringInSlice([]string{
+					"all-buckets",
+					"bucket-prefix",
+					"bucket-names",
+					"policy",
+				}, false),
+			},
+			"bucket": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"ids": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"names": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"permissions": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"description": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"type": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"ready_state": {
+							Type:     schema.TypeBool,
+							Computed: true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourcePermissionsRead(d *schema.ResourceData, meta interface{}) error {
+	if CheckCredentials(AccountAPI, meta.(Client)) {
+		return fmt.Errorf("credentials for account api are missing")
+	}
+
+	conn := *meta.(Client).AccountAPIClient
+
+	permissions, err := conn.ListPermissions()
+	if err != nil {
+		return fmt.Errorf("error listing permissions: %w", err)
+	}
+
+	var nameRegex *regexp.Regexp
+	if v, ok := d.GetOk("name_regex"); ok {
+		nameRegex = regexp.MustCompile(v.(string))
+	}
+	permissionType := d.Get("type").(string)
+	bucket := d.Get("bucket").(string)
+
+	ids := []string{}
+	names := []string{}
+	list := []interface{}{}
+	for _, permission := range permissions {
+		if nameRegex != nil && !nameRegex.MatchString(permission.Name) {
+			continue
+		}
+
+		if permissionType != "" && permission.Type != permissionType {
+			continue
+		}
+
+		if bucket != "" {
+			// the list response does not always carry buckets, prefix and policy.
+			resp, err := conn.GetPermission(permission.Id)
+			if err != nil {
+				return fmt.Errorf("error reading permission (%s): %w", permission.Id, err)
+			}
+
+			ok, err := permissionReferencesBucket(resp, bucket)
+			if err != nil {
+				return fmt.Errorf("error reading permission (%s): %w", permission.Id, err)
+			}
+
+			if !ok {
+				continue
+			}
+		}
+
+		ids = append(ids, permission.Id)
+		names = append(names, permission.Name)
+		list = append(list, map[string]interface{}{
+			"id":          permission.Id,
+			"name":        permission.Name,
+			"description": permission.Description,
+			"type":        permission.Type,
+			"ready_state": permission.ReadyState,
+		})
+	}
+
+	d.SetId(strconv.Itoa(schema.HashString(strings.Join(ids, ","))))
+	d.Set("ids", ids)
+	d.Set("names", names)
+
+	if err := d.Set("permissions", list); err != nil {
+		return fmt.Errorf("error setting permissions: %w", err)
+	}
+
+	return nil
+}
+
+// permissionReferencesBucket returns true if the permission grants access to the given bucket.
+func permissionReferencesBucket(permission *GetPermissionResponse, bucket string) (bool, error) {
+	switch permission.Type {
+	case "all-buckets":
+		return true, nil
+	case "bucket-prefix":
+		return strings.HasPrefix(bucket, permission.Prefix), nil
+	case "bucket-names":
+		for _, v := range permission.Buckets {
+			if v == bucket {
+				return true, nil
+			}
+		}
+		return false, nil
+	case "policy":
+		policy, err := unescape(permission.Policy)
+		if err != nil {
+			return false, fmt.Errorf("Error parsing policy: %s", err)
+		}
+		return policyReferencesBucket(policy, bucket)
+	}
+
+	return false, nil
+}
+
+// policyReferencesBucket returns true if any Allow statement of the policy has a resource matching the given bucket.
+func policyReferencesBucket(policy, bucket string) (bool, error) {
+	if strings.TrimSpace(policy) == "" {
+		return false, nil
+	}
+
+	type policyStatement struct {
+		Effect   string
+		Resource interface{}
+	}
+
+	var document struct {
+		Statement json.RawMessage
+	}
+	if err := json.Unmarshal([]byte(policy), &document); err != nil {
+		return false, fmt.Errorf("policy (%s) is invalid JSON: %w", policy, err)
+	}
+
+	if len(document.Statement) == 0 {
+		return false, nil
+	}
+
+	// Statement may be a single statement or a list of statements.
+	var statements []policyStatement
+	if err := json.Unmarshal(document.Statement, &statements); err != nil {
+		var statement policyStatement
+		if err := json.Unmarshal(document.Statement, &statement); err != nil {
+			return false, fmt.Errorf("policy (%s) has invalid statements: %w", policy, err)
+		}
+		statements = []policyStatement{statement}
+	}
+
+	for _, statement := range statements {
+		if statement.Effect != "Allow" {
+			continue
+		}
+
+		var resources []interface{}
+		switch v := statement.Resource.(type) {
+		case string:
+			resources = []interface{}{v}
+		case []interface{}:
+			resources = v
+		}
+
+		for _, v := range resources {
+			resource, ok := v.(string)
+			if !ok {
+				continue
+			}
+
+			if resource == "*" {
+				return true, nil
+			}
+
+			pattern := strings.SplitN(strings.TrimPrefix(resource, "arn:aws:s3:::"), "/", 2)[0]
+			if matched, _ := path.Match(pattern, bucket); matched {
+				return true, nil
+			}
+		}
+	}
+
+	return false, nil
+}
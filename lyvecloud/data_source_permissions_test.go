@@ -0,0 +1,80 @@
+package lyvecloud
+
+import (
+	"testing"
+)
+
+func TestPermissionReferencesBucket(t *testing.T) {
+	testCases := []struct {
+		Name       string
+		Permission GetPermissionResponse
+		Expected   bool
+	}{
+		{
+			Name:       "all buckets",
+			Permission: GetPermissionResponse{Type: "all-buckets"},
+			Expected:   true,
+		},
+		{
+			Name:       "matching prefix",
+			Permission: GetPermissionResponse{Type: "bucket-prefix", Prefix: "team-a-"},
+			Expected:   true,
+		},
+		{
+			Name:       "other prefix",
+			Permission: GetPermissionResponse{Type: "bucket-prefix", Prefix: "team-b-"},
+		},
+		{
+			Name:       "listed bucket",
+			Permission: GetPermissionResponse{Type: "bucket-names", Buckets: []string{"other", "team-a-logs"}},
+			Expected:   true,
+		},
+		{
+			Name:       "unlisted bucket",
+			Permission: GetPermissionResponse{Type: "bucket-names", Buckets: []string{"other"}},
+		},
+		{
+			Name: "policy bucket arn",
+			Permission: GetPermissionResponse{
+				Type:   "policy",
+				Policy: `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":"s3:GetObject","Resource":["arn:aws:s3:::team-a-logs/*"]}]}`,
+			},
+			Expected: true,
+		},
+		{
+			Name: "policy wildcard arn",
+			Permission: GetPermissionResponse{
+				Type:   "policy",
+				Policy: `{"Version":"2012-10-17","Statement":{"Effect":"Allow","Action":"s3:*","Resource":"arn:aws:s3:::team-a-*"}}`,
+			},
+			Expected: true,
+		},
+		{
+			Name: "policy deny only",
+			Permission: GetPermissionResponse{
+				Type:   "policy",
+				Policy: `{"Version":"2012-10-17","Statement":[{"Effect":"Deny","Action":"s3:*","Resource":"arn:aws:s3:::team-a-logs"}]}`,
+			},
+		},
+		{
+			Name: "policy other bucket",
+			Permission: GetPermissionResponse{
+				Type:   "policy",
+				Policy: `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":"s3:*","Resource":"arn:aws:s3:::team-b-logs/*"}]}`,
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			got, err := permissionReferencesBucket(&testCase.Permission, "team-a-logs")
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if got != testCase.Expected {
+				t.Errorf("got %t, expected %t", got, testCase.Expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,263 @@
+package lyvecloud
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+const policyVersion = "2012-10-17"
+
+func DataSourcePolicyDocument() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourcePolicyDocumentRead,
+		Schema: map[string]*schema.Schema{
+			"policy_id": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"version": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      policyVersion,
+				ValidateFunc: validation.StringInSlice([]string{policyVersion}, false),
+			},
+			"source_policy_documents": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validation.StringIsJSON,
+				},
+			},
+			"override_policy_documents": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validation.StringIsJSON,
+				},
+			},
+			"statement": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"sid": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"effect": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							Default:      "Allow",
+							ValidateFunc: validation.StringInSlice([]string{"Allow", "Deny"}, false),
+						},
+						"actions": {
+							Type:     schema.TypeSet,
+							Required: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+						},
+						"resources": {
+							Type:     schema.TypeSet,
+							Required: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+						},
+						"conditions": {
+							Type:     schema.TypeList,
+							Optional: true,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"test": {
+										Type:     schema.TypeString,
+										Required: true,
+									},
+									"variable": {
+										Type:     schema.TypeString,
+										Required: true,
+									},
+									"values": {
+										Type:     schema.TypeSet,
+										Required: true,
+										Elem:     &schema.Schema{Type: schema.TypeString},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			"json": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func dataSourcePolicyDocumentRead(d *schema.ResourceData, meta interface{}) error {
+	var statements []map[string]interface{}
+
+	for i, v := range d.Get("source_policy_documents").([]interface{}) {
+		source, err := policyDocumentStatements(v.(string))
+		if err != nil {
+			return fmt.Errorf("error reading source policy document %d: %w", i, err)
+		}
+
+		for _, statement := range source {
+			if sid := policyStatementSid(statement); sid != "" && policyStatementIndex(statements, sid) >= 0 {
+				return fmt.Errorf("duplicate Sid (%s) in source policy documents", sid)
+			}
+			statements = append(statements, statement)
+		}
+	}
+
+	// statement blocks replace the source statements with the same Sid, but not each other.
+	sids := map[string]bool{}
+	for _, v := range d.Get("statement").([]interface{}) {
+		statement := expandPolicyStatement(v.(map[string]interface{}))
+		if sid := policyStatementSid(statement); sid != "" {
+			if sids[sid] {
+				return fmt.Errorf("duplicate sid (%s) in statement blocks", sid)
+			}
+			sids[sid] = true
+		}
+		statements = mergePolicyStatement(statements, statement)
+	}
+
+	for i, v := range d.Get("override_policy_documents").([]interface{}) {
+		override, err := policyDocumentStatements(v.(string))
+		if err != nil {
+			return fmt.Errorf("error reading override policy document %d: %w", i, err)
+		}
+
+		for _, statement := range override {
+			statements = mergePolicyStatement(statements, statement)
+		}
+	}
+
+	document := map[string]interface{}{
+		"Version":   d.Get("version").(string),
+		"Statement": statements,
+	}
+	if statements == nil {
+		document["Statement"] = []interface{}{}
+	}
+	if v, ok := d.GetOk("policy_id"); ok {
+		document["Id"] = v.(string)
+	}
+
+	bytes, err := json.Marshal(document)
+	if err != nil {
+		return fmt.Errorf("error encoding policy document: %w", err)
+	}
+
+	// normalize the same way as the policy of lyvecloud_permission, so the output never shows a diff there.
+	policyJSON, err := NormalizeJsonString(string(bytes))
+	if err != nil {
+		return fmt.Errorf("policy (%s) is invalid JSON: %w", policyJSON, err)
+	}
+
+	d.Set("json", policyJSON)
+	d.SetId(strconv.Itoa(schema.HashString(policyJSON)))
+
+	return nil
+}
+
+// expandPolicyStatement converts a statement block to a policy statement.
+func expandPolicyStatement(tfMap map[string]interface{}) map[string]interface{} {
+	statement := map[string]interface{}{
+		"Effect":   tfMap["effect"].(string),
+		"Action":   expandStringSet(tfMap["actions"].(*schema.Set)),
+		"Resource": expandStringSet(tfMap["resources"].(*schema.Set)),
+	}
+
+	if sid := tfMap["sid"].(string); sid != "" {
+		statement["Sid"] = sid
+	}
+
+	conditions := map[string]interface{}{}
+	for _, v := range tfMap["conditions"].([]interface{}) {
+		condition := v.(map[string]interface{})
+		test := condition["test"].(string)
+		variable := condition["variable"].(string)
+
+		if _, ok := conditions[test]; !ok {
+			conditions[test] = map[string]interface{}{}
+		}
+		variables := conditions[test].(map[string]interface{})
+
+		// conditions with the same test and variable are merged, without duplicate values.
+		values := condition["values"].(*schema.Set)
+		if existing, ok := variables[variable]; ok {
+			values = existing.(*schema.Set).Union(values)
+		}
+		variables[variable] = values
+	}
+	for _, v := range conditions {
+		variables := v.(map[string]interface{})
+		for variable, values := range variables {
+			variables[variable] = expandStringSet(values.(*schema.Set))
+		}
+	}
+	if len(conditions) > 0 {
+		statement["Condition"] = conditions
+	}
+
+	return statement
+}
+
+// policyDocumentStatements returns the statements of a JSON policy document.
+func policyDocumentStatements(policy string) ([]map[string]interface{}, error) {
+	var document struct {
+		Statement json.RawMessage
+	}
+	if err := json.Unmarshal([]byte(policy), &document); err != nil {
+		return nil, fmt.Errorf("policy (%s) is invalid JSON: %w", policy, err)
+	}
+
+	if len(document.Statement) == 0 {
+		return nil, nil
+	}
+
+	// Statement may be a single statement or a list of statements.
+	var statements []map[string]interface{}
+	if err := json.Unmarshal(document.Statement, &statements); err != nil {
+		var statement map[string]interface{}
+		if err := json.Unmarshal(document.Statement, &statement); err != nil {
+			return nil, fmt.Errorf("policy (%s) has invalid statements: %w", policy, err)
+		}
+		statements = []map[string]interface{}{statement}
+	}
+
+	return statements, nil
+}
+
+// mergePolicyStatement replaces the statement with the same Sid, or appends the statement if there is none.
+func mergePolicyStatement(statements []map[string]interface{}, statement map[string]interface{}) []map[string]interface{} {
+	if sid := policyStatementSid(statement); sid != "" {
+		if i := policyStatementIndex(statements, sid); i >= 0 {
+			statements[i] = statement
+			return statements
+		}
+	}
+
+	return append(statements, statement)
+}
+
+func policyStatementIndex(statements []map[string]interface{}, sid string) int {
+	for i, statement := range statements {
+		if policyStatementSid(statement) == sid {
+			return i
+		}
+	}
+	return -1
+}
+
+func policyStatementSid(statement map[string]interface{}) string {
+	sid, _ := statement["Sid"].(string)
+	return sid
+}
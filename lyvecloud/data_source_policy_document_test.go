@@ -0,0 +1,186 @@
+package lyvecloud
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestDataSourcePolicyDocumentRead(t *testing.T) {
+	testCases := []struct {
+		Name          string
+		Raw           map[string]interface{}
+		Expected      string
+		ExpectedError string
+	}{
+		{
+			Name: "statements",
+			Raw: map[string]interface{}{
+				"statement": []interface{}{
+					map[string]interface{}{
+						"sid":       "list",
+						"actions":   []interface{}{"s3:ListBucket"},
+						"resources": []interface{}{"arn:aws:s3:::mybucket"},
+						"conditions": []interface{}{
+							map[string]interface{}{
+								"test":     "StringLike",
+								"variable": "s3:prefix",
+								"values":   []interface{}{"David/*"},
+							},
+						},
+					},
+					map[string]interface{}{
+						"effect":    "Deny",
+						"actions":   []interface{}{"s3:PutObject", "s3:DeleteObject"},
+						"resources": []interface{}{"arn:aws:s3:::mybucket/David/*"},
+					},
+				},
+			},
+			Expected: `{"Statement":[{"Action":["s3:ListBucket"],"Condition":{"StringLike":{"s3:prefix":["David/*"]}},"Effect":"Allow","Resource":["arn:aws:s3:::mybucket"],"Sid":"list"},{"Action":["s3:DeleteObject","s3:PutObject"],"Effect":"Deny","Resource":["arn:aws:s3:::mybucket/David/*"]}],"Version":"2012-10-17"}`,
+		},
+		{
+			Name: "source and override",
+			Raw: map[string]interface{}{
+				"source_policy_documents": []interface{}{
+					`{"Version":"2012-10-17","Statement":[{"Sid":"read","Effect":"Allow","Action":"s3:GetObject","Resource":"arn:aws:s3:::a/*"},{"Sid":"write","Effect":"Allow","Action":"s3:PutObject","Resource":"arn:aws:s3:::a/*"}]}`,
+				},
+				"statement": []interface{}{
+					map[string]interface{}{
+						"sid":       "read",
+						"actions":   []interface{}{"s3:GetObject"},
+						"resources": []interface{}{"arn:aws:s3:::b/*"},
+					},
+				},
+				"override_policy_documents": []interface{}{
+					`{"Version":"2012-10-17","Statement":{"Sid":"write","Effect":"Deny","Action":"s3:PutObject","Resource":"arn:aws:s3:::a/*"}}`,
+				},
+			},
+			Expected: `{"Statement":[{"Action":["s3:GetObject"],"Effect":"Allow","Resource":["arn:aws:s3:::b/*"],"Sid":"read"},{"Action":"s3:PutObject","Effect":"Deny","Resource":"arn:aws:s3:::a/*","Sid":"write"}],"Version":"2012-10-17"}`,
+		},
+		{
+			Name: "repeated conditions",
+			Raw: map[string]interface{}{
+				"statement": []interface{}{
+					map[string]interface{}{
+						"actions":   []interface{}{"s3:ListBucket"},
+						"resources": []interface{}{"arn:aws:s3:::mybucket"},
+						"conditions": []interface{}{
+							map[string]interface{}{
+								"test":     "StringLike",
+								"variable": "s3:prefix",
+								"values":   []interface{}{"David/*", "home/"},
+							},
+							map[string]interface{}{
+								"test":     "StringLike",
+								"variable": "s3:prefix",
+								"values":   []interface{}{"home/", "Anna/*"},
+							},
+						},
+					},
+				},
+			},
+			Expected: `{"Statement":[{"Action":["s3:ListBucket"],"Condition":{"StringLike":{"s3:prefix":["Anna/*","David/*","home/"]}},"Effect":"Allow","Resource":["arn:aws:s3:::mybucket"]}],"Version":"2012-10-17"}`,
+		},
+		{
+			Name: "duplicate sid",
+			Raw: map[string]interface{}{
+				"statement": []interface{}{
+					map[string]interface{}{
+						"sid":       "read",
+						"actions":   []interface{}{"s3:GetObject"},
+						"resources": []interface{}{"arn:aws:s3:::a/*"},
+					},
+					map[string]interface{}{
+						"sid":       "read",
+						"actions":   []interface{}{"s3:GetObject"},
+						"resources": []interface{}{"arn:aws:s3:::b/*"},
+					},
+				},
+			},
+			ExpectedError: "duplicate sid (read) in statement blocks",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			d := schema.TestResourceDataRaw(t, DataSourcePolicyDocument().Schema, testCase.Raw)
+
+			err := dataSourcePolicyDocumentRead(d, nil)
+			if testCase.ExpectedError != "" {
+				if err == nil || !strings.Contains(err.Error(), testCase.ExpectedError) {
+					t.Fatalf("got error %v, expected it to contain %q", err, testCase.ExpectedError)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			got := d.Get("json").(string)
+			if got != testCase.Expected {
+				t.Errorf("got %s, expected %s", got, testCase.Expected)
+			}
+
+			normalized, err := NormalizeJsonString(got)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if normalized != got {
+				t.Errorf("normalized policy %s differs from %s", normalized, got)
+			}
+
+			policyToSet, err := PolicyToSet(got, normalized)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if policyToSet != got {
+				t.Errorf("policy to set %s differs from %s", policyToSet, got)
+			}
+		})
+	}
+}
+
+func TestAccPolicyDocumentDataSource_basic(t *testing.T) {
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccPolicyDocumentDataSourceConfig_basic,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.lyvecloud_policy_document.david", "json", `{"Statement":[{"Action":["s3:ListBucket"],"Condition":{"StringLike":{"s3:prefix":["David/*"]}},"Effect":"Allow","Resource":["arn:aws:s3:::mybucket"],"Sid":"statement1"},{"Action":["s3:GetObject","s3:PutObject"],"Effect":"Allow","Resource":["arn:aws:s3:::mybucket/David/*"],"Sid":"statement2"}],"Version":"2012-10-17"}`),
+					resource.TestCheckResourceAttrPair("data.lyvecloud_policy_document.merged", "json", "data.lyvecloud_policy_document.david", "json"),
+				),
+			},
+		},
+	})
+}
+
+// testAccPolicyDocumentDataSourceConfig_basic is the example of the documentation of the data source.
+const testAccPolicyDocumentDataSourceConfig_basic = `
+data "lyvecloud_policy_document" "david" {
+  statement {
+    sid       = "statement1"
+    actions   = ["s3:ListBucket"]
+    resources = ["arn:aws:s3:::mybucket"]
+
+    conditions {
+      test     = "StringLike"
+      variable = "s3:prefix"
+      values   = ["David/*"]
+    }
+  }
+
+  statement {
+    sid       = "statement2"
+    actions   = ["s3:GetObject", "s3:PutObject"]
+    resources = ["arn:aws:s3:::mybucket/David/*"]
+  }
+}
+
+data "lyvecloud_policy_document" "merged" {
+  source_policy_documents = [data.lyvecloud_policy_document.david.json]
+}
+`
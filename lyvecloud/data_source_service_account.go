@@ -0,0 +1,77 @@
+package lyvecloud
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func DataSourceServiceAccount() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceServiceAccountRead,
+		Schema: map[string]*schema.Schema{
+			"id": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ExactlyOneOf: []string{"id", "name"},
+			},
+			"name": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ExactlyOneOf: []string{"id", "name"},
+			},
+			"description": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"enabled": {
+				Type:     schema.TypeBool,
+				Computed: true,
+			},
+			"ready_state": {
+				Type:     schema.TypeBool,
+				Computed: true,
+			},
+			"permissions": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+		},
+	}
+}
+
+func dataSourceServiceAccountRead(d *schema.ResourceData, meta interface{}) error {
+	if CheckCredentials(AccountAPI, meta.(Client)) {
+		return fmt.Errorf("credentials for account api are missing")
+	}
+
+	conn := *meta.(Client).AccountAPIClient
+
+	serviceAccountId := d.Get("id").(string)
+	if name, ok := d.GetOk("name"); ok {
+		serviceAccount, err := findServiceAccountByName(&conn, name.(string))
+		if err != nil {
+			return err
+		}
+		serviceAccountId = serviceAccount.Id
+	}
+
+	resp, err := conn.GetServiceAccount(serviceAccountId)
+	if err != nil {
+		return fmt.Errorf("error reading service account (%s): %w", serviceAccountId, err)
+	}
+
+	d.SetId(resp.Id)
+	d.Set("name", resp.Name)
+	d.Set("description", resp.Description)
+	d.Set("enabled", resp.Enabled)
+	d.Set("ready_state", resp.ReadyState)
+	d.Set("permissions", resp.Permissions)
+
+	return nil
+}
@@ -0,0 +1,103 @@
+package lyvecloud
+
+import (
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestDataSourceServiceAccountRead(t *testing.T) {
+	testCases := []struct {
+		Name          string
+		Raw           map[string]interface{}
+		ExpectedId    string
+		ExpectedError string
+	}{
+		{
+			Name:       "by id",
+			Raw:        map[string]interface{}{"id": "sa-1"},
+			ExpectedId: "sa-1",
+		},
+		{
+			Name:       "by name",
+			Raw:        map[string]interface{}{"name": "logs-writer"},
+			ExpectedId: "sa-1",
+		},
+		{
+			Name:          "id not found",
+			Raw:           map[string]interface{}{"id": "sa-missing"},
+			ExpectedError: "error reading service account (sa-missing)",
+		},
+		{
+			Name:          "name not found",
+			Raw:           map[string]interface{}{"name": "missing"},
+			ExpectedError: "service account (missing) not found",
+		},
+		{
+			Name:          "ambiguous name",
+			Raw:           map[string]interface{}{"name": "shared"},
+			ExpectedError: "multiple service accounts named shared: sa-2, sa-3",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			newFakeAccountAPI(t, map[string]http.HandlerFunc{
+				"GET /v2/service-accounts": jsonHandler(http.StatusOK, []GetServiceAccountResponse{
+					{Id: "sa-1", Name: "logs-writer"},
+					{Id: "sa-2", Name: "shared"},
+					{Id: "sa-3", Name: "shared"},
+				}),
+				"GET /v2/service-accounts/sa-1": jsonHandler(http.StatusOK, GetServiceAccountResponse{
+					Id:          "sa-1",
+					Name:        "logs-writer",
+					Description: "writes logs",
+					Enabled:     true,
+					ReadyState:  true,
+					Permissions: []string{"p-1", "p-2"},
+				}),
+			})
+
+			d := schema.TestResourceDataRaw(t, DataSourceServiceAccount().Schema, testCase.Raw)
+			err := dataSourceServiceAccountRead(d, Client{AccountAPIClient: &AuthData{}})
+			if testCase.ExpectedError != "" {
+				if err == nil || !strings.Contains(err.Error(), testCase.ExpectedError) {
+					t.Fatalf("got error %v, expected it to contain %q", err, testCase.ExpectedError)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if d.Id() != testCase.ExpectedId {
+				t.Errorf("got id %q, expected %q", d.Id(), testCase.ExpectedId)
+			}
+
+			expected := map[string]interface{}{
+				"name":        "logs-writer",
+				"description": "writes logs",
+				"enabled":     true,
+				"ready_state": true,
+			}
+
+			for key, value := range expected {
+				if got := d.Get(key); got != value {
+					t.Errorf("got %s %v, expected %v", key, got, value)
+				}
+			}
+
+			if got := d.Get("permissions").([]interface{}); !reflect.DeepEqual(got, []interface{}{"p-1", "p-2"}) {
+				t.Errorf("got permissions %v, expected [p-1 p-2]", got)
+			}
+
+			// the secret is never exposed.
+			if _, ok := DataSourceServiceAccount().Schema["secret"]; ok {
+				t.Error("expected no secret attribute")
+			}
+		})
+	}
+}
@@ -0,0 +1,166 @@
+package lyvecloud
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func DataSourceServiceAccounts() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceServiceAccountsRead,
+		Schema: map[string]*schema.Schema{
+			"name_regex": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringIsValidRegExp,
+			},
+			"enabled": {
+				Type:     schema.TypeBool,
+				Optional: true,
+			},
+			"bucket": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"ids": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"names": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"service_accounts": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"description": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"enabled": {
+							Type:     schema.TypeBool,
+							Computed: true,
+						},
+						"ready_state": {
+							Type:     schema.TypeBool,
+							Computed: true,
+						},
+						"permissions": {
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceServiceAccountsRead(d *schema.ResourceData, meta interface{}) error {
+	if CheckCredentials(AccountAPI, meta.(Client)) {
+		return fmt.Errorf("credentials for account api are missing")
+	}
+
+	conn := *meta.(Client).AccountAPIClient
+
+	serviceAccounts, err := conn.ListServiceAccounts()
+	if err != nil {
+		return fmt.Errorf("error listing service accounts: %w", err)
+	}
+
+	var nameRegex *regexp.Regexp
+	if v, ok := d.GetOk("name_regex"); ok {
+		nameRegex = regexp.MustCompile(v.(string))
+	}
+	bucket := d.Get("bucket").(string)
+
+	// permissions already checked against bucket, shared across service accounts.
+	referencesBucket := map[string]bool{}
+
+	ids := []string{}
+	names := []string{}
+	list := []interface{}{}
+	for _, summary := range serviceAccounts {
+		if nameRegex != nil && !nameRegex.MatchString(summary.Name) {
+			continue
+		}
+
+		// GetOkExists is the only way to tell an explicit false from an unset argument.
+		if v, ok := d.GetOkExists("enabled"); ok && summary.Enabled != v.(bool) { //nolint:staticcheck
+			continue
+		}
+
+		// the list response does not carry the permissions of each service account.
+		serviceAccount, err := conn.GetServiceAccount(summary.Id)
+		if err != nil {
+			return fmt.Errorf("error reading service account (%s): %w", summary.Id, err)
+		}
+
+		if bucket != "" {
+			found := false
+			for _, permissionId := range serviceAccount.Permissions {
+				ok, checked := referencesBucket[permissionId]
+				if !checked {
+					permission, err := conn.GetPermission(permissionId)
+					if err != nil {
+						return fmt.Errorf("error reading permission (%s): %w", permissionId, err)
+					}
+
+					ok, err = permissionReferencesBucket(permission, bucket)
+					if err != nil {
+						return fmt.Errorf("error reading permission (%s): %w", permissionId, err)
+					}
+					referencesBucket[permissionId] = ok
+				}
+
+				if ok {
+					found = true
+					break
+				}
+			}
+
+			if !found {
+				continue
+			}
+		}
+
+		ids = append(ids, serviceAccount.Id)
+		names = append(names, serviceAccount.Name)
+		list = append(list, map[string]interface{}{
+			"id":          serviceAccount.Id,
+			"name":        serviceAccount.Name,
+			"description": serviceAccount.Description,
+			"enabled":     serviceAccount.Enabled,
+			"ready_state": serviceAccount.ReadyState,
+			"permissions": serviceAccount.Permissions,
+		})
+	}
+
+	d.SetId(strconv.Itoa(schema.HashString(strings.Join(ids, ","))))
+	d.Set("ids", ids)
+	d.Set("names", names)
+
+	if err := d.Set("service_accounts", list); err != nil {
+		return fmt.Errorf("error setting service accounts: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,85 @@
+package lyvecloud
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestDataSourceServiceAccountsRead(t *testing.T) {
+	testCases := []struct {
+		Name            string
+		Raw             map[string]interface{}
+		ExpectedIds     []string
+		ExpectedDetails int
+	}{
+		{
+			Name:            "no filter",
+			Raw:             map[string]interface{}{},
+			ExpectedIds:     []string{"sa-1", "sa-2"},
+			ExpectedDetails: 2,
+		},
+		{
+			Name:            "name filter",
+			Raw:             map[string]interface{}{"name_regex": "^logs"},
+			ExpectedIds:     []string{"sa-1"},
+			ExpectedDetails: 1,
+		},
+		{
+			Name:            "bucket filter",
+			Raw:             map[string]interface{}{"bucket": "logs"},
+			ExpectedIds:     []string{"sa-1"},
+			ExpectedDetails: 2,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			api := newFakeAccountAPI(t, map[string]http.HandlerFunc{
+				"GET /v2/service-accounts": jsonHandler(http.StatusOK, []GetServiceAccountResponse{
+					{Id: "sa-1", Name: "logs-writer"},
+					{Id: "sa-2", Name: "backup"},
+				}),
+				"GET /v2/service-accounts/sa-1": jsonHandler(http.StatusOK, GetServiceAccountResponse{Id: "sa-1", Name: "logs-writer", Permissions: []string{"p-1"}}),
+				"GET /v2/service-accounts/sa-2": jsonHandler(http.StatusOK, GetServiceAccountResponse{Id: "sa-2", Name: "backup", Permissions: []string{"p-2"}}),
+				"GET /v2/permissions/p-1":       jsonHandler(http.StatusOK, GetPermissionResponse{Id: "p-1", Type: "bucket-names", Buckets: []string{"logs"}}),
+				"GET /v2/permissions/p-2":       jsonHandler(http.StatusOK, GetPermissionResponse{Id: "p-2", Type: "bucket-names", Buckets: []string{"backup"}}),
+			})
+
+			d := schema.TestResourceDataRaw(t, DataSourceServiceAccounts().Schema, testCase.Raw)
+			if err := dataSourceServiceAccountsRead(d, Client{AccountAPIClient: &AuthData{}}); err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			ids, err := convertPermissionsList(d.Get("ids").([]interface{}))
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if !reflect.DeepEqual(ids, testCase.ExpectedIds) {
+				t.Errorf("got ids %v, expected %v", ids, testCase.ExpectedIds)
+			}
+
+			// permissions are set whatever the filters.
+			if got := d.Get("service_accounts.0.permissions").([]interface{}); !reflect.DeepEqual(got, []interface{}{"p-1"}) {
+				t.Errorf("got permissions %v, expected [p-1]", got)
+			}
+
+			details := api.count("GET /v2/service-accounts/sa-1") + api.count("GET /v2/service-accounts/sa-2")
+			if details != testCase.ExpectedDetails {
+				t.Errorf("got %d service account reads, expected %d", details, testCase.ExpectedDetails)
+			}
+
+			id := d.Id()
+			if err := dataSourceServiceAccountsRead(d, Client{AccountAPIClient: &AuthData{}}); err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if d.Id() != id {
+				t.Errorf("got ID %s on second read, expected %s", d.Id(), id)
+			}
+		})
+	}
+}
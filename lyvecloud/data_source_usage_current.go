@@ -0,0 +1,91 @@
+package lyvecloud
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// Lyve Cloud bills storage in decimal units.
+const gigabytesPerTerabyte = 1000
+
+func DataSourceUsageCurrent() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceUsageCurrentRead,
+		Schema: map[string]*schema.Schema{
+			"total_usage_gb": {
+				Type:     schema.TypeFloat,
+				Computed: true,
+			},
+			"total_usage_tb": {
+				Type:     schema.TypeFloat,
+				Computed: true,
+			},
+			"num_buckets": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"buckets": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     bucketUsageSchema(),
+			},
+		},
+	}
+}
+
+func dataSourceUsageCurrentRead(d *schema.ResourceData, meta interface{}) error {
+	if CheckCredentials(AccountAPI, meta.(Client)) {
+		return fmt.Errorf("credentials for account api are missing")
+	}
+
+	conn := *meta.(Client).AccountAPIClient
+
+	resp, err := conn.GetCurrentUsage()
+	if err != nil {
+		return fmt.Errorf("error reading current usage: %w", err)
+	}
+
+	// the response doesn't identify the billing period, there is a single current usage per account.
+	d.SetId("current")
+	d.Set("total_usage_gb", resp.Usage.TotalUsageGB)
+	d.Set("total_usage_tb", resp.Usage.TotalUsageGB/gigabytesPerTerabyte)
+	d.Set("num_buckets", resp.Usage.NumBuckets)
+
+	if err := d.Set("buckets", flattenBucketUsage(resp.Usage.UsageByBucket)); err != nil {
+		return fmt.Errorf("error setting buckets: %w", err)
+	}
+
+	return nil
+}
+
+func bucketUsageSchema() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"usage_gb": {
+				Type:     schema.TypeFloat,
+				Computed: true,
+			},
+			"usage_tb": {
+				Type:     schema.TypeFloat,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func flattenBucketUsage(usage []BucketUsage) []interface{} {
+	buckets := make([]interface{}, 0, len(usage))
+	for _, v := range usage {
+		buckets = append(buckets, map[string]interface{}{
+			"name":     v.Name,
+			"usage_gb": v.UsageGB,
+			"usage_tb": v.UsageGB / gigabytesPerTerabyte,
+		})
+	}
+	return buckets
+}
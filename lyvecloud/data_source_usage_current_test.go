@@ -0,0 +1,55 @@
+package lyvecloud
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestDataSourceUsageCurrentRead(t *testing.T) {
+	newFakeAccountAPI(t, map[string]http.HandlerFunc{
+		"GET /v2/usage/current": func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"usage":{"totalUsageGB":1500.5,"numBuckets":2,"usageByBucket":[{"name":"logs","usageGB":1000},{"name":"backup","usageGB":500.5}]}}`))
+		},
+	})
+
+	d := schema.TestResourceDataRaw(t, DataSourceUsageCurrent().Schema, map[string]interface{}{})
+	if err := dataSourceUsageCurrentRead(d, Client{AccountAPIClient: &AuthData{}}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	expected := map[string]interface{}{
+		"total_usage_gb":     1500.5,
+		"total_usage_tb":     1.5005,
+		"num_buckets":        2,
+		"buckets.#":          2,
+		"buckets.0.name":     "logs",
+		"buckets.0.usage_tb": 1.0,
+		"buckets.1.name":     "backup",
+		"buckets.1.usage_gb": 500.5,
+	}
+
+	for key, value := range expected {
+		if got := d.Get(key); got != value {
+			t.Errorf("got %s %v, expected %v", key, got, value)
+		}
+	}
+}
+
+func TestGetCurrentUsageInvalidResponse(t *testing.T) {
+	newFakeAccountAPI(t, map[string]http.HandlerFunc{
+		"GET /v2/usage/current": func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"usage":{"totalUsageGB":"a lot"}}`))
+		},
+	})
+
+	conn := &AuthData{}
+	_, err := conn.GetCurrentUsage()
+	if err == nil || !strings.Contains(err.Error(), "totalUsageGB") {
+		t.Errorf("expected error about totalUsageGB, got %v", err)
+	}
+}
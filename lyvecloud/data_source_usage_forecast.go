@@ -0,0 +1,246 @@
+package lyvecloud
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func DataSourceUsageForecast() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceUsageForecastRead,
+		Schema: map[string]*schema.Schema{
+			"history_months": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      6,
+				ValidateFunc: validation.IntBetween(2, 60),
+			},
+			"forecast_months": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      3,
+				ValidateFunc: validation.IntBetween(1, 60),
+			},
+			"price_per_tb": {
+				Type:         schema.TypeFloat,
+				Optional:     true,
+				Default:      0,
+				ValidateFunc: validation.FloatAtLeast(0),
+			},
+			"trend_tb_per_month": {
+				Type:     schema.TypeFloat,
+				Computed: true,
+			},
+			"projections": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     usageProjectionSchema(),
+			},
+			"buckets": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"bucket": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"trend_tb_per_month": {
+							Type:     schema.TypeFloat,
+							Computed: true,
+						},
+						"projections": {
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem:     usageProjectionSchema(),
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceUsageForecastRead(d *schema.ResourceData, meta interface{}) error {
+	if CheckCredentials(AccountAPI, meta.(Client)) {
+		return fmt.Errorf("credentials for account api are missing")
+	}
+
+	conn := *meta.(Client).AccountAPIClient
+
+	historyMonths := d.Get("history_months").(int)
+	forecastMonths := d.Get("forecast_months").(int)
+	pricePerTB := d.Get("price_per_tb").(float64)
+
+	// the current month is incomplete, so the history ends with the previous month.
+	now := time.Now().UTC()
+	to := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC).AddDate(0, -1, 0)
+	from := to.AddDate(0, -(historyMonths - 1), 0)
+
+	resp, err := conn.GetUsageByDate(Dates{
+		FromMonth: int(from.Month()),
+		FromYear:  from.Year(),
+		ToMonth:   int(to.Month()),
+		ToYear:    to.Year(),
+	})
+	if err != nil {
+		return fmt.Errorf("error reading usage from %s to %s: %w", from.Format(usageMonthLayout), to.Format(usageMonthLayout), err)
+	}
+
+	total, byBucket := usageHistory(resp.Usage, from, historyMonths)
+	if len(total) < 2 {
+		return fmt.Errorf("error forecasting usage: %d of the %d months from %s to %s are reported, at least 2 are needed to fit a trend", len(total), historyMonths, from.Format(usageMonthLayout), to.Format(usageMonthLayout))
+	}
+
+	firstMonth := to.AddDate(0, 1, 0)
+
+	slope, intercept := linearTrend(total)
+	d.Set("trend_tb_per_month", slope)
+	if err := d.Set("projections", flattenUsageProjections(slope, intercept, historyMonths, forecastMonths, firstMonth, pricePerTB)); err != nil {
+		return fmt.Errorf("error setting projections: %w", err)
+	}
+
+	bucketNames := make([]string, 0, len(byBucket))
+	for name := range byBucket {
+		bucketNames = append(bucketNames, name)
+	}
+	sort.Strings(bucketNames)
+
+	buckets := make([]interface{}, 0, len(bucketNames))
+	for _, name := range bucketNames {
+		slope, intercept := linearTrend(byBucket[name])
+		buckets = append(buckets, map[string]interface{}{
+			"bucket":             name,
+			"trend_tb_per_month": slope,
+			"projections":        flattenUsageProjections(slope, intercept, historyMonths, forecastMonths, firstMonth, pricePerTB),
+		})
+	}
+
+	if err := d.Set("buckets", buckets); err != nil {
+		return fmt.Errorf("error setting buckets: %w", err)
+	}
+
+	d.SetId(fmt.Sprintf("%s/%s/%d", from.Format(usageMonthLayout), to.Format(usageMonthLayout), forecastMonths))
+
+	return nil
+}
+
+func usageProjectionSchema() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"month": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"projected_tb": {
+				Type:     schema.TypeFloat,
+				Computed: true,
+			},
+			"estimated_cost": {
+				Type:     schema.TypeFloat,
+				Computed: true,
+			},
+		},
+	}
+}
+
+// flattenUsageProjections projects the trend over forecastMonths months following a history of historyMonths months.
+func flattenUsageProjections(slope, intercept float64, historyMonths, forecastMonths int, firstMonth time.Time, pricePerTB float64) []interface{} {
+	projections := make([]interface{}, 0, forecastMonths)
+	for i := 0; i < forecastMonths; i++ {
+		// stored capacity can't be negative, even when the trend is shrinking.
+		projected := math.Max(0, intercept+slope*float64(historyMonths+i))
+
+		projections = append(projections, map[string]interface{}{
+			"month":          firstMonth.AddDate(0, i, 0).Format(usageMonthLayout),
+			"projected_tb":   projected,
+			"estimated_cost": projected * pricePerTB,
+		})
+	}
+	return projections
+}
+
+// usagePoint is the stored capacity, in TB, of a month of the history, indexed from its first month.
+type usagePoint struct {
+	month int
+	tb    float64
+}
+
+// usageHistory returns the usage points of the account and of each bucket for the historyMonths months starting at from.
+// Months missing from the response are skipped rather than counted as no usage, which would skew the trend.
+// Within a reported month, a bucket missing from the response has no usage.
+func usageHistory(usage []MonthlyUsage, from time.Time, historyMonths int) ([]usagePoint, map[string][]usagePoint) {
+	reported := map[int]MonthlyUsage{}
+	for _, v := range usage {
+		i := monthsBetween(from, time.Date(v.Year, time.Month(v.Month), 1, 0, 0, 0, 0, time.UTC))
+		if i < 0 || i >= historyMonths {
+			continue
+		}
+		reported[i] = v
+	}
+
+	bucketNames := map[string]bool{}
+	for _, v := range reported {
+		for _, bucket := range v.UsageByBucket {
+			bucketNames[bucket.Name] = true
+		}
+	}
+
+	total := []usagePoint{}
+	byBucket := map[string][]usagePoint{}
+	for i := 0; i < historyMonths; i++ {
+		v, ok := reported[i]
+		if !ok {
+			continue
+		}
+
+		total = append(total, usagePoint{month: i, tb: v.TotalUsageGB / gigabytesPerTerabyte})
+
+		usageByBucket := map[string]float64{}
+		for _, bucket := range v.UsageByBucket {
+			usageByBucket[bucket.Name] = bucket.UsageGB / gigabytesPerTerabyte
+		}
+		for name := range bucketNames {
+			byBucket[name] = append(byBucket[name], usagePoint{month: i, tb: usageByBucket[name]})
+		}
+	}
+
+	return total, byBucket
+}
+
+// linearTrend fits a least squares line through points, returning its slope and intercept.
+func linearTrend(points []usagePoint) (float64, float64) {
+	n := float64(len(points))
+	if n == 0 {
+		return 0, 0
+	}
+
+	var sumX, sumY, sumXY, sumXX float64
+	for _, point := range points {
+		x, y := float64(point.month), point.tb
+		sumX += x
+		sumY += y
+		sumXY += x * y
+		sumXX += x * x
+	}
+
+	denominator := n*sumXX - sumX*sumX
+	if denominator == 0 {
+		return 0, sumY / n
+	}
+
+	slope := (n*sumXY - sumX*sumY) / denominator
+	intercept := (sumY - slope*sumX) / n
+
+	return slope, intercept
+}
+
+// monthsBetween returns the number of months from from to to.
+func monthsBetween(from, to time.Time) int {
+	return (to.Year()-from.Year())*12 + int(to.Month()) - int(from.Month())
+}
@@ -0,0 +1,109 @@
+package lyvecloud
+
+import (
+	"math"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestLinearTrend(t *testing.T) {
+	testCases := []struct {
+		Name      string
+		Points    []usagePoint
+		Slope     float64
+		Intercept float64
+	}{
+		{
+			Name: "no values",
+		},
+		{
+			Name:      "single value",
+			Points:    []usagePoint{{0, 4}},
+			Intercept: 4,
+		},
+		{
+			Name:      "flat",
+			Points:    []usagePoint{{0, 2}, {1, 2}, {2, 2}},
+			Intercept: 2,
+		},
+		{
+			Name:      "growing",
+			Points:    []usagePoint{{0, 1}, {1, 3}, {2, 5}, {3, 7}},
+			Slope:     2,
+			Intercept: 1,
+		},
+		{
+			Name:      "noisy",
+			Points:    []usagePoint{{0, 1}, {1, 2}, {2, 2}, {3, 3}},
+			Slope:     0.6,
+			Intercept: 1.1,
+		},
+		{
+			Name:      "missing month",
+			Points:    []usagePoint{{0, 1}, {1, 3}, {3, 7}},
+			Slope:     2,
+			Intercept: 1,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			slope, intercept := linearTrend(testCase.Points)
+
+			if math.Abs(slope-testCase.Slope) > 1e-9 || math.Abs(intercept-testCase.Intercept) > 1e-9 {
+				t.Errorf("got slope %f intercept %f, expected slope %f intercept %f", slope, intercept, testCase.Slope, testCase.Intercept)
+			}
+		})
+	}
+}
+
+func TestUsageHistory(t *testing.T) {
+	from := time.Date(2023, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	usage := []MonthlyUsage{
+		{Year: 2023, Month: 1, Usage: Usage{TotalUsageGB: 1000, UsageByBucket: []BucketUsage{{Name: "logs", UsageGB: 1000}}}},
+		// February is missing from the response.
+		{Year: 2023, Month: 3, Usage: Usage{TotalUsageGB: 3000, UsageByBucket: []BucketUsage{{Name: "logs", UsageGB: 2000}, {Name: "backup", UsageGB: 1000}}}},
+		{Year: 2022, Month: 12, Usage: Usage{TotalUsageGB: 9000}},
+	}
+
+	total, byBucket := usageHistory(usage, from, 3)
+
+	if expected := []usagePoint{{0, 1}, {2, 3}}; !reflect.DeepEqual(total, expected) {
+		t.Errorf("got total %v, expected %v", total, expected)
+	}
+
+	expectedByBucket := map[string][]usagePoint{
+		"logs":   {{0, 1}, {2, 2}},
+		"backup": {{0, 0}, {2, 1}},
+	}
+	if !reflect.DeepEqual(byBucket, expectedByBucket) {
+		t.Errorf("got buckets %v, expected %v", byBucket, expectedByBucket)
+	}
+}
+
+func TestFlattenUsageProjections(t *testing.T) {
+	firstMonth := time.Date(2023, time.December, 1, 0, 0, 0, 0, time.UTC)
+
+	got := flattenUsageProjections(-1, 4, 3, 3, firstMonth, 10)
+
+	expected := []map[string]interface{}{
+		{"month": "2023-12", "projected_tb": 1.0, "estimated_cost": 10.0},
+		{"month": "2024-01", "projected_tb": 0.0, "estimated_cost": 0.0},
+		{"month": "2024-02", "projected_tb": 0.0, "estimated_cost": 0.0},
+	}
+
+	if len(got) != len(expected) {
+		t.Fatalf("got %d projections, expected %d", len(got), len(expected))
+	}
+
+	for i, v := range got {
+		projection := v.(map[string]interface{})
+		for k, e := range expected[i] {
+			if projection[k] != e {
+				t.Errorf("projection %d: got %s %v, expected %v", i, k, projection[k], e)
+			}
+		}
+	}
+}
@@ -0,0 +1,165 @@
+package lyvecloud
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+const usageMonthLayout = "2006-01"
+
+var usageMonthRegexp = regexp.MustCompile(`^\d{4}-(0[1-9]|1[0-2])$`)
+
+func DataSourceUsageMonthly() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceUsageMonthlyRead,
+		Schema: map[string]*schema.Schema{
+			"from": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.StringMatch(usageMonthRegexp, "must be a month in the format YYYY-MM"),
+			},
+			"to": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.StringMatch(usageMonthRegexp, "must be a month in the format YYYY-MM"),
+			},
+			"months": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"month": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"total_usage_gb": {
+							Type:     schema.TypeFloat,
+							Computed: true,
+						},
+						"total_usage_tb": {
+							Type:     schema.TypeFloat,
+							Computed: true,
+						},
+						"num_buckets": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+					},
+				},
+			},
+			"records": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"month": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"bucket": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"usage_gb": {
+							Type:     schema.TypeFloat,
+							Computed: true,
+						},
+						"usage_tb": {
+							Type:     schema.TypeFloat,
+							Computed: true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceUsageMonthlyRead(d *schema.ResourceData, meta interface{}) error {
+	if CheckCredentials(AccountAPI, meta.(Client)) {
+		return fmt.Errorf("credentials for account api are missing")
+	}
+
+	conn := *meta.(Client).AccountAPIClient
+
+	from := d.Get("from").(string)
+	to := d.Get("to").(string)
+
+	dates, err := expandUsageDates(from, to, time.Now().UTC())
+	if err != nil {
+		return err
+	}
+
+	resp, err := conn.GetUsageByDate(dates)
+	if err != nil {
+		return fmt.Errorf("error reading usage from %s to %s: %w", from, to, err)
+	}
+
+	months := []interface{}{}
+	records := []interface{}{}
+	for _, usage := range resp.Usage {
+		month := fmt.Sprintf("%04d-%02d", usage.Year, usage.Month)
+
+		months = append(months, map[string]interface{}{
+			"month":          month,
+			"total_usage_gb": usage.TotalUsageGB,
+			"total_usage_tb": usage.TotalUsageGB / gigabytesPerTerabyte,
+			"num_buckets":    usage.NumBuckets,
+		})
+
+		for _, bucket := range usage.UsageByBucket {
+			records = append(records, map[string]interface{}{
+				"month":    month,
+				"bucket":   bucket.Name,
+				"usage_gb": bucket.UsageGB,
+				"usage_tb": bucket.UsageGB / gigabytesPerTerabyte,
+			})
+		}
+	}
+
+	d.SetId(from + "/" + to)
+
+	if err := d.Set("months", months); err != nil {
+		return fmt.Errorf("error setting months: %w", err)
+	}
+
+	if err := d.Set("records", records); err != nil {
+		return fmt.Errorf("error setting records: %w", err)
+	}
+
+	return nil
+}
+
+// expandUsageDates converts a from/to range of YYYY-MM months to Dates,
+// rejecting reversed ranges and months after the current month.
+func expandUsageDates(from, to string, now time.Time) (Dates, error) {
+	fromMonth, err := time.Parse(usageMonthLayout, from)
+	if err != nil {
+		return Dates{}, fmt.Errorf("invalid from month (%s): %w", from, err)
+	}
+
+	toMonth, err := time.Parse(usageMonthLayout, to)
+	if err != nil {
+		return Dates{}, fmt.Errorf("invalid to month (%s): %w", to, err)
+	}
+
+	if toMonth.Before(fromMonth) {
+		return Dates{}, fmt.Errorf("usage range is reversed: from (%s) is after to (%s)", from, to)
+	}
+
+	currentMonth := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	if toMonth.After(currentMonth) {
+		return Dates{}, fmt.Errorf("usage range is in the future: to (%s) is after the current month (%s)", to, currentMonth.Format(usageMonthLayout))
+	}
+
+	return Dates{
+		FromMonth: int(fromMonth.Month()),
+		FromYear:  fromMonth.Year(),
+		ToMonth:   int(toMonth.Month()),
+		ToYear:    toMonth.Year(),
+	}, nil
+}
@@ -0,0 +1,65 @@
+package lyvecloud
+
+import (
+	"testing"
+	"time"
+)
+
+func TestExpandUsageDates(t *testing.T) {
+	now := time.Date(2023, time.March, 15, 0, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		Name        string
+		From        string
+		To          string
+		Expected    Dates
+		ExpectError bool
+	}{
+		{
+			Name:     "single month",
+			From:     "2023-01",
+			To:       "2023-01",
+			Expected: Dates{FromMonth: 1, FromYear: 2023, ToMonth: 1, ToYear: 2023},
+		},
+		{
+			Name:     "across years up to current month",
+			From:     "2022-11",
+			To:       "2023-03",
+			Expected: Dates{FromMonth: 11, FromYear: 2022, ToMonth: 3, ToYear: 2023},
+		},
+		{
+			Name:        "reversed",
+			From:        "2023-02",
+			To:          "2023-01",
+			ExpectError: true,
+		},
+		{
+			Name:        "future",
+			From:        "2023-01",
+			To:          "2023-04",
+			ExpectError: true,
+		},
+		{
+			Name:        "invalid month",
+			From:        "2023-13",
+			To:          "2023-01",
+			ExpectError: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			got, err := expandUsageDates(testCase.From, testCase.To, now)
+
+			if testCase.ExpectError && err == nil {
+				t.Fatal("expected error")
+			} else if !testCase.ExpectError && err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if got != testCase.Expected {
+				t.Errorf("got %+v, expected %+v", got, testCase.Expected)
+			}
+		})
+	}
+}
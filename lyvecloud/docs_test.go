@@ -0,0 +1,201 @@
+package lyvecloud
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+var docsExampleRegexp = regexp.MustCompile("(?s)```(?:terraform|hcl)\n(.*?)```")
+
+// TestDocsExamples checks the Terraform examples of the documentation against the provider schema: the resource and
+// data source types must be registered, their arguments and blocks must exist, required arguments must be set,
+// and references to lyvecloud resources and data sources must name registered types and existing attributes.
+func TestDocsExamples(t *testing.T) {
+	provider := Provider()
+
+	files, err := filepath.Glob(filepath.Join("..", "docs", "*", "*.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	files = append(files, filepath.Join("..", "docs", "index.md"))
+
+	for _, file := range files {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for i, match := range docsExampleRegexp.FindAllStringSubmatch(strings.ReplaceAll(string(content), "\r\n", "\n"), -1) {
+			name := fmt.Sprintf("%s#%d", filepath.ToSlash(file), i)
+
+			t.Run(name, func(t *testing.T) {
+				f, diags := hclsyntax.ParseConfig([]byte(match[1]), name, hcl.InitialPos)
+				if diags.HasErrors() {
+					t.Fatalf("invalid HCL: %s", diags)
+				}
+
+				for _, err := range checkDocsExample(provider, f.Body.(*hclsyntax.Body)) {
+					t.Error(err)
+				}
+			})
+		}
+	}
+}
+
+func checkDocsExample(provider *schema.Provider, body *hclsyntax.Body) []error {
+	var errs []error
+
+	for _, block := range body.Blocks {
+		switch {
+		case block.Type == "provider" && len(block.Labels) == 1 && block.Labels[0] == "lyvecloud":
+			errs = append(errs, checkDocsExampleBody("provider", provider.Schema, block.Body)...)
+		case (block.Type == "resource" || block.Type == "data") && len(block.Labels) == 2 && strings.HasPrefix(block.Labels[0], "lyvecloud_"):
+			r := docsExampleResource(provider, block.Type, block.Labels[0])
+			if r == nil {
+				errs = append(errs, fmt.Errorf("%s %q is not registered in the provider", block.Type, block.Labels[0]))
+				continue
+			}
+			errs = append(errs, checkDocsExampleBody(block.Type+"."+block.Labels[0], r.Schema, block.Body)...)
+		}
+	}
+
+	hclsyntax.VisitAll(body, func(node hclsyntax.Node) hcl.Diagnostics {
+		expr, ok := node.(*hclsyntax.ScopeTraversalExpr)
+		if !ok {
+			return nil
+		}
+
+		if err := checkDocsExampleReference(provider, expr.Traversal); err != nil {
+			errs = append(errs, err)
+		}
+		return nil
+	})
+
+	return errs
+}
+
+// docsExampleMetaArguments are the arguments and blocks handled by Terraform rather than by the provider.
+var docsExampleMetaArguments = map[string]bool{
+	"count":      true,
+	"for_each":   true,
+	"depends_on": true,
+	"provider":   true,
+	"lifecycle":  true,
+	"timeouts":   true,
+}
+
+func checkDocsExampleBody(path string, schemaMap map[string]*schema.Schema, body *hclsyntax.Body) []error {
+	var errs []error
+
+	for name := range body.Attributes {
+		s, ok := schemaMap[name]
+		if docsExampleMetaArguments[name] {
+			continue
+		}
+		if !ok || (s.Computed && !s.Optional) {
+			errs = append(errs, fmt.Errorf("%s: unsupported argument %q", path, name))
+		}
+	}
+
+	for _, block := range body.Blocks {
+		if docsExampleMetaArguments[block.Type] {
+			continue
+		}
+
+		s, ok := schemaMap[block.Type]
+		if !ok {
+			errs = append(errs, fmt.Errorf("%s: unsupported block %q", path, block.Type))
+			continue
+		}
+
+		elem, ok := s.Elem.(*schema.Resource)
+		if !ok {
+			errs = append(errs, fmt.Errorf("%s: %q is not a block", path, block.Type))
+			continue
+		}
+
+		errs = append(errs, checkDocsExampleBody(path+"."+block.Type, elem.Schema, block.Body)...)
+	}
+
+	names := make([]string, 0, len(schemaMap))
+	for name := range schemaMap {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		// Required arguments with a DefaultFunc can be set in the environment.
+		if !schemaMap[name].Required || schemaMap[name].DefaultFunc != nil {
+			continue
+		}
+		if _, ok := body.Attributes[name]; ok {
+			continue
+		}
+		found := false
+		for _, block := range body.Blocks {
+			found = found || block.Type == name
+		}
+		if !found {
+			errs = append(errs, fmt.Errorf("%s: missing required argument %q", path, name))
+		}
+	}
+
+	return errs
+}
+
+// checkDocsExampleReference checks references to lyvecloud resources and data sources, e.g. data.lyvecloud_x.name.attr.
+func checkDocsExampleReference(provider *schema.Provider, traversal hcl.Traversal) error {
+	var names []string
+	for _, step := range traversal {
+		switch step := step.(type) {
+		case hcl.TraverseRoot:
+			names = append(names, step.Name)
+		case hcl.TraverseAttr:
+			names = append(names, step.Name)
+		default:
+			// indexes end the attribute path.
+			names = append(names, "")
+		}
+	}
+
+	blockType := "resource"
+	if len(names) > 0 && names[0] == "data" {
+		blockType = "data"
+		names = names[1:]
+	}
+
+	if len(names) == 0 || !strings.HasPrefix(names[0], "lyvecloud_") {
+		return nil
+	}
+
+	r := docsExampleResource(provider, blockType, names[0])
+	if r == nil {
+		return fmt.Errorf("reference to %s %q, which is not registered in the provider", blockType, names[0])
+	}
+
+	if len(names) < 3 || names[2] == "" || names[2] == "id" {
+		return nil
+	}
+
+	if _, ok := r.Schema[names[2]]; !ok {
+		return fmt.Errorf("reference to unsupported attribute %q of %s %q", names[2], blockType, names[0])
+	}
+
+	return nil
+}
+
+func docsExampleResource(provider *schema.Provider, blockType, typeName string) *schema.Resource {
+	if blockType == "data" {
+		return provider.DataSourcesMap[typeName]
+	}
+	return provider.ResourcesMap[typeName]
+}
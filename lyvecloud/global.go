@@ -5,6 +5,10 @@ import "github.com/aws/aws-sdk-go/service/s3"
 type Client struct {
 	S3Client         *s3.S3
 	AccountAPIClient *AuthData
+	ReadOnly         bool
+
+	// AllowedBucketPatterns restricts the buckets resources can target, nil if all buckets are allowed.
+	AllowedBucketPatterns []bucketPattern
 }
 
 const (
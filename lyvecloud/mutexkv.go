@@ -0,0 +1,62 @@
+package lyvecloud
+
+import (
+	"log"
+	"sync"
+)
+
+// MutexKV is a simple key/value store for arbitrary mutexes. It serializes
+// read-modify-write changes to the same Account API object across resources.
+// The mutexes are local to the provider process, so changes made by other
+// processes, e.g. other Terraform runs or provider configurations, are not serialized.
+type MutexKV struct {
+	lock  sync.Mutex
+	store map[string]*sync.Mutex
+}
+
+// accountAPIMutexKV serializes changes to service accounts and permissions.
+var accountAPIMutexKV = NewMutexKV()
+
+// Locks the mutex for the given key. Caller is responsible for calling Unlock
+// for the same key.
+func (m *MutexKV) Lock(key string) {
+	log.Printf("[DEBUG] Locking %q", key)
+	m.get(key).Lock()
+	log.Printf("[DEBUG] Locked %q", key)
+}
+
+// Unlocks the mutex for the given key. Caller must have called Lock for the same key first.
+func (m *MutexKV) Unlock(key string) {
+	log.Printf("[DEBUG] Unlocking %q", key)
+	m.get(key).Unlock()
+	log.Printf("[DEBUG] Unlocked %q", key)
+}
+
+// Returns a mutex for the given key, no guarantee of its lock status.
+func (m *MutexKV) get(key string) *sync.Mutex {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	mutex, ok := m.store[key]
+	if !ok {
+		mutex = &sync.Mutex{}
+		m.store[key] = mutex
+	}
+	return mutex
+}
+
+// Returns a properly initialized MutexKV.
+func NewMutexKV() *MutexKV {
+	return &MutexKV{
+		store: make(map[string]*sync.Mutex),
+	}
+}
+
+// serviceAccountMutexKey returns the key that serializes changes to a service account.
+func serviceAccountMutexKey(serviceAccountId string) string {
+	return "service-account/" + serviceAccountId
+}
+
+// permissionMutexKey returns the key that serializes changes to a permission.
+func permissionMutexKey(permissionId string) string {
+	return "permission/" + permissionId
+}
@@ -0,0 +1,136 @@
+package lyvecloud
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/hashicorp/aws-sdk-go-base/v2/awsv1shim/v2/tfawserr"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// resourcePermissionBucketsCustomizeDiff fails the plan when validate_buckets is set and
+// a listed bucket, or any bucket matching the prefix, doesn't exist.
+func resourcePermissionBucketsCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if !d.Get("validate_buckets").(bool) {
+		return nil
+	}
+
+	if !d.HasChanges("validate_buckets", "buckets", "bucket_prefix") {
+		return nil
+	}
+
+	if !d.NewValueKnown("buckets") || !d.NewValueKnown("bucket_prefix") {
+		return nil
+	}
+
+	client, ok := meta.(Client)
+	if !ok {
+		return nil
+	}
+
+	if CheckCredentials(S3, client) {
+		return fmt.Errorf("validate_buckets requires the s3 block of the provider, credentials for S3 operations are missing")
+	}
+
+	buckets, err := convertBucketsList(d.Get("buckets").(*schema.Set).List())
+	if err != nil {
+		return fmt.Errorf("error reading buckets list: %w", err)
+	}
+
+	missing, err := missingPermissionBuckets(ctx, client.S3Client, buckets, d.Get("bucket_prefix").(string))
+	if err != nil {
+		return err
+	}
+
+	if len(missing) > 0 {
+		return fmt.Errorf("permission would grant nothing on: %s", strings.Join(missing, ", "))
+	}
+
+	return nil
+}
+
+// permissionBucketsDriftDiags returns a warning for each bucket referenced by the permission that no longer exists.
+func permissionBucketsDriftDiags(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	if d.Id() == "" || !d.Get("warn_on_bucket_drift").(bool) {
+		return nil
+	}
+
+	if CheckCredentials(S3, meta.(Client)) {
+		return diag.Diagnostics{{
+			Severity: diag.Warning,
+			Summary:  "Unable to check permission buckets",
+			Detail:   fmt.Sprintf("warn_on_bucket_drift is set on permission (%s), but credentials for S3 operations are missing.", d.Id()),
+		}}
+	}
+
+	buckets, err := convertBucketsList(d.Get("buckets").(*schema.Set).List())
+	if err != nil {
+		return diag.Errorf("error reading buckets list: %s", err)
+	}
+
+	missing, err := missingPermissionBuckets(ctx, meta.(Client).S3Client, buckets, d.Get("bucket_prefix").(string))
+	if err != nil {
+		return diag.Diagnostics{{
+			Severity: diag.Warning,
+			Summary:  "Unable to check permission buckets",
+			Detail:   fmt.Sprintf("error checking buckets of permission (%s): %s", d.Id(), err),
+		}}
+	}
+
+	var diags diag.Diagnostics
+	for _, v := range missing {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Warning,
+			Summary:  "Permission references a missing bucket",
+			Detail:   fmt.Sprintf("permission (%s) grants nothing on %s.", d.Id(), v),
+		})
+	}
+
+	return diags
+}
+
+// missingPermissionBuckets returns the buckets that don't exist and, if no bucket starts with prefix, the prefix.
+func missingPermissionBuckets(ctx context.Context, conn *s3.S3, buckets []string, prefix string) ([]string, error) {
+	var missing []string
+
+	for _, bucket := range buckets {
+		_, err := conn.HeadBucketWithContext(ctx, &s3.HeadBucketInput{
+			Bucket: aws.String(bucket),
+		})
+
+		if tfawserr.ErrStatusCodeEquals(err, http.StatusNotFound) || tfawserr.ErrCodeEquals(err, s3.ErrCodeNoSuchBucket) {
+			missing = append(missing, fmt.Sprintf("bucket %q", bucket))
+			continue
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("error reading S3 Bucket (%s): %w", bucket, err)
+		}
+	}
+
+	if prefix != "" {
+		output, err := conn.ListBucketsWithContext(ctx, &s3.ListBucketsInput{})
+		if err != nil {
+			return nil, fmt.Errorf("error listing S3 Buckets: %w", err)
+		}
+
+		found := false
+		for _, bucket := range output.Buckets {
+			if strings.HasPrefix(aws.StringValue(bucket.Name), prefix) {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			missing = append(missing, fmt.Sprintf("bucket prefix %q", prefix))
+		}
+	}
+
+	return missing, nil
+}
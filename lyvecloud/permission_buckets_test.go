@@ -0,0 +1,187 @@
+package lyvecloud
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+// newFakeS3Client returns an S3 client whose requests are served by the handlers of newFakeAccountAPI,
+// by method and path style bucket path, e.g. "HEAD /my-bucket".
+func newFakeS3Client(t *testing.T) *s3.S3 {
+	// the S3 client can't load a custom CA bundle into the transport of the fake API.
+	t.Setenv("AWS_CA_BUNDLE", "")
+
+	conn, err := createS3ClientWithCredentials("us-east-1", credentials.NewStaticCredentials("access-key", "secret-key", ""), "s3.us-east-1.lyvecloud.seagate.com")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	return conn
+}
+
+// fakeS3Buckets returns the handlers of an S3 API holding the given buckets.
+func fakeS3Buckets(buckets ...string) map[string]http.HandlerFunc {
+	list := `<ListAllMyBucketsResult><Buckets>`
+	handlers := map[string]http.HandlerFunc{}
+	for _, bucket := range buckets {
+		list += `<Bucket><Name>` + bucket + `</Name></Bucket>`
+		handlers["HEAD /"+bucket] = func(w http.ResponseWriter, r *http.Request) {}
+	}
+	list += `</Buckets></ListAllMyBucketsResult>`
+
+	handlers["GET /"] = func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(list))
+	}
+	return handlers
+}
+
+func TestMissingPermissionBuckets(t *testing.T) {
+	testCases := []struct {
+		Name     string
+		Buckets  []string
+		Prefix   string
+		Expected []string
+	}{
+		{
+			Name:    "existing buckets",
+			Buckets: []string{"team-a-logs", "team-a-backup"},
+		},
+		{
+			Name:     "missing bucket",
+			Buckets:  []string{"team-a-logs", "team-a-lgos"},
+			Expected: []string{`bucket "team-a-lgos"`},
+		},
+		{
+			Name:   "matching prefix",
+			Prefix: "team-a-",
+		},
+		{
+			Name:     "prefix matching no bucket",
+			Prefix:   "team-b-",
+			Expected: []string{`bucket prefix "team-b-"`},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			// HEAD requests on other buckets get a 404.
+			newFakeAccountAPI(t, fakeS3Buckets("team-a-logs", "team-a-backup"))
+
+			missing, err := missingPermissionBuckets(context.Background(), newFakeS3Client(t), testCase.Buckets, testCase.Prefix)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if !reflect.DeepEqual(missing, testCase.Expected) {
+				t.Errorf("got missing %q, expected %q", missing, testCase.Expected)
+			}
+		})
+	}
+}
+
+func TestResourcePermissionCustomizeDiff_validateBuckets(t *testing.T) {
+	testCases := []struct {
+		Name          string
+		Buckets       []interface{}
+		NoS3          bool
+		ExpectedError string
+	}{
+		{
+			Name:    "existing buckets",
+			Buckets: []interface{}{"team-a-logs"},
+		},
+		{
+			Name:          "missing bucket",
+			Buckets:       []interface{}{"team-a-logs", "team-a-lgos"},
+			ExpectedError: `permission would grant nothing on: bucket "team-a-lgos"`,
+		},
+		{
+			Name:          "missing S3 credentials",
+			Buckets:       []interface{}{"team-a-logs"},
+			NoS3:          true,
+			ExpectedError: "validate_buckets requires the s3 block of the provider",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			newFakeAccountAPI(t, fakeS3Buckets("team-a-logs"))
+
+			meta := Client{S3Client: newFakeS3Client(t)}
+			if testCase.NoS3 {
+				meta.S3Client = nil
+			}
+
+			raw := map[string]interface{}{
+				"description":      "description",
+				"actions":          "read-only",
+				"buckets":          testCase.Buckets,
+				"validate_buckets": true,
+			}
+
+			_, err := ResourcePermission().Diff(context.Background(), nil, terraform.NewResourceConfigRaw(raw), meta)
+			if testCase.ExpectedError == "" && err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if testCase.ExpectedError != "" && (err == nil || !strings.Contains(err.Error(), testCase.ExpectedError)) {
+				t.Fatalf("got error %v, expected it to contain %q", err, testCase.ExpectedError)
+			}
+		})
+	}
+}
+
+func TestPermissionBucketsDriftDiags(t *testing.T) {
+	testCases := []struct {
+		Name     string
+		Raw      map[string]interface{}
+		Expected []string
+	}{
+		{
+			Name: "drift disabled",
+			Raw:  map[string]interface{}{"buckets": []interface{}{"team-a-deleted"}},
+		},
+		{
+			Name: "existing buckets",
+			Raw:  map[string]interface{}{"buckets": []interface{}{"team-a-logs"}, "warn_on_bucket_drift": true},
+		},
+		{
+			Name:     "deleted bucket",
+			Raw:      map[string]interface{}{"buckets": []interface{}{"team-a-logs", "team-a-deleted"}, "warn_on_bucket_drift": true},
+			Expected: []string{`permission (permission-id) grants nothing on bucket "team-a-deleted".`},
+		},
+		{
+			Name:     "deleted prefix",
+			Raw:      map[string]interface{}{"bucket_prefix": "team-b-", "warn_on_bucket_drift": true},
+			Expected: []string{`permission (permission-id) grants nothing on bucket prefix "team-b-".`},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			newFakeAccountAPI(t, fakeS3Buckets("team-a-logs"))
+
+			d := schema.TestResourceDataRaw(t, ResourcePermission().Schema, testCase.Raw)
+			d.SetId("permission-id")
+
+			var got []string
+			for _, diagnostic := range permissionBucketsDriftDiags(context.Background(), d, Client{S3Client: newFakeS3Client(t)}) {
+				if diagnostic.Severity != diag.Warning {
+					t.Errorf("got %v diagnostic %q, expected warnings", diagnostic.Severity, diagnostic.Detail)
+				}
+				got = append(got, diagnostic.Detail)
+			}
+
+			if !reflect.DeepEqual(got, testCase.Expected) {
+				t.Errorf("got warnings %q, expected %q", got, testCase.Expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,268 @@
+package lyvecloud
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// supportedPolicyActions are the S3 actions Lyve Cloud accepts in permission policies.
+var supportedPolicyActions = []string{
+	"s3:AbortMultipartUpload",
+	"s3:BypassGovernanceRetention",
+	"s3:CreateBucket",
+	"s3:DeleteBucket",
+	"s3:DeleteBucketPolicy",
+	"s3:DeleteObject",
+	"s3:DeleteObjectTagging",
+	"s3:DeleteObjectVersion",
+	"s3:DeleteObjectVersionTagging",
+	"s3:GetBucketLocation",
+	"s3:GetBucketObjectLockConfiguration",
+	"s3:GetBucketPolicy",
+	"s3:GetBucketTagging",
+	"s3:GetBucketVersioning",
+	"s3:GetLifecycleConfiguration",
+	"s3:GetObject",
+	"s3:GetObjectLegalHold",
+	"s3:GetObjectRetention",
+	"s3:GetObjectTagging",
+	"s3:GetObjectVersion",
+	"s3:GetObjectVersionTagging",
+	"s3:ListAllMyBuckets",
+	"s3:ListBucket",
+	"s3:ListBucketMultipartUploads",
+	"s3:ListBucketVersions",
+	"s3:ListMultipartUploadParts",
+	"s3:PutBucketObjectLockConfiguration",
+	"s3:PutBucketPolicy",
+	"s3:PutBucketTagging",
+	"s3:PutBucketVersioning",
+	"s3:PutLifecycleConfiguration",
+	"s3:PutObject",
+	"s3:PutObjectLegalHold",
+	"s3:PutObjectRetention",
+	"s3:PutObjectTagging",
+	"s3:PutObjectVersionTagging",
+}
+
+// policyResourceRegexp matches bucket and object ARNs, wildcards included.
+var policyResourceRegexp = regexp.MustCompile(`^arn:aws:s3:::([a-z0-9*?][a-z0-9.\-*?]{0,62})(/.*)?$`)
+
+// resourcePermissionPolicyCustomizeDiff checks the resources of a changed policy against what Lyve Cloud supports at plan time.
+// Unsupported actions are reported by validatePermissionPolicyDiag.
+func resourcePermissionPolicyCustomizeDiff(_ context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if !d.HasChange("policy") || !d.NewValueKnown("policy") {
+		return nil
+	}
+
+	policy := d.Get("policy").(string)
+	if strings.TrimSpace(policy) == "" {
+		return nil
+	}
+
+	var errs *multierror.Error
+	for _, err := range validatePermissionPolicy(policy, true) {
+		errs = multierror.Append(errs, err)
+	}
+
+	if err := errs.ErrorOrNil(); err != nil {
+		return fmt.Errorf("policy is not supported by Lyve Cloud: %w", err)
+	}
+
+	return nil
+}
+
+// validatePermissionPolicyDiag is the ValidateDiagFunc of policy. Structural problems and actions that aren't
+// supported by Lyve Cloud are errors, failing the plan rather than the apply.
+func validatePermissionPolicyDiag(v interface{}, path cty.Path) diag.Diagnostics {
+	var diags diag.Diagnostics
+	for _, err := range validatePermissionPolicy(v.(string), false) {
+		diags = append(diags, diag.Diagnostic{
+			Severity:      diag.Error,
+			Summary:       "Invalid policy",
+			Detail:        err.Error(),
+			AttributePath: path,
+		})
+	}
+
+	if diags.HasError() {
+		return diags
+	}
+
+	for _, unsupported := range unsupportedPolicyActions(v.(string)) {
+		diags = append(diags, diag.Diagnostic{
+			Severity:      diag.Error,
+			Summary:       "Policy action not supported by Lyve Cloud",
+			Detail:        unsupported,
+			AttributePath: path,
+		})
+	}
+	return diags
+}
+
+// unsupportedPolicyActions describes the actions of a structurally valid policy that don't match a supported action.
+func unsupportedPolicyActions(policy string) []string {
+	statements, err := policyDocumentStatements(policy)
+	if err != nil {
+		return nil
+	}
+
+	var unsupported []string
+	for i, statement := range statements {
+		actionKey, actions, _ := policyStatementElement(statement, "Action", "NotAction")
+		for _, action := range actions {
+			if !policyActionSupported(action) {
+				unsupported = append(unsupported, fmt.Sprintf("%s: %s %q is not supported", policyStatementName(i, statement), actionKey, action))
+			}
+		}
+	}
+	return unsupported
+}
+
+// validatePermissionPolicy checks the structure of a policy and, if supported is true,
+// that its resources are supported by Lyve Cloud.
+func validatePermissionPolicy(policy string, supported bool) []error {
+	if strings.TrimSpace(policy) == "" {
+		return nil
+	}
+
+	var document map[string]interface{}
+	if err := json.Unmarshal([]byte(policy), &document); err != nil {
+		return []error{fmt.Errorf("policy is invalid JSON: %w", err)}
+	}
+
+	var errs []error
+
+	if version, ok := document["Version"]; !ok {
+		errs = append(errs, fmt.Errorf("Version must be set to %q", policyVersion))
+	} else if version != policyVersion {
+		errs = append(errs, fmt.Errorf("Version %v is not supported, must be %q", version, policyVersion))
+	}
+
+	statements, err := policyDocumentStatements(policy)
+	if err != nil {
+		return append(errs, err)
+	}
+
+	if len(statements) == 0 {
+		return append(errs, fmt.Errorf("Statement must contain at least one statement"))
+	}
+
+	for i, statement := range statements {
+		for _, err := range validatePolicyStatement(statement, supported) {
+			errs = append(errs, fmt.Errorf("%s: %w", policyStatementName(i, statement), err))
+		}
+	}
+
+	return errs
+}
+
+func validatePolicyStatement(statement map[string]interface{}, supported bool) []error {
+	var errs []error
+
+	if effect, ok := statement["Effect"]; !ok {
+		errs = append(errs, fmt.Errorf("Effect must be set"))
+	} else if effect != "Allow" && effect != "Deny" {
+		errs = append(errs, fmt.Errorf("Effect %v is not supported, must be \"Allow\" or \"Deny\"", effect))
+	}
+
+	for _, key := range []string{"Principal", "NotPrincipal"} {
+		if _, ok := statement[key]; ok {
+			errs = append(errs, fmt.Errorf("%s is not supported, permission policies apply to the service accounts they are attached to", key))
+		}
+	}
+
+	if _, _, err := policyStatementElement(statement, "Action", "NotAction"); err != nil {
+		errs = append(errs, err)
+	}
+
+	resourceKey, resources, err := policyStatementElement(statement, "Resource", "NotResource")
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	if !supported {
+		return errs
+	}
+
+	for _, resource := range resources {
+		if resource != "*" && !policyResourceRegexp.MatchString(resource) {
+			errs = append(errs, fmt.Errorf("%s %q is not a valid bucket or object ARN (arn:aws:s3:::bucket or arn:aws:s3:::bucket/key)", resourceKey, resource))
+		}
+	}
+
+	return errs
+}
+
+// policyStatementElement returns which of key or notKey is set and its values, which may be a string or a list of strings.
+func policyStatementElement(statement map[string]interface{}, key, notKey string) (string, []string, error) {
+	v, ok := statement[key]
+	notV, notOk := statement[notKey]
+
+	if ok && notOk {
+		return key, nil, fmt.Errorf("only one of %s or %s can be set", key, notKey)
+	}
+
+	if !ok && !notOk {
+		return key, nil, fmt.Errorf("one of %s or %s must be set", key, notKey)
+	}
+
+	if notOk {
+		key, v = notKey, notV
+	}
+
+	switch v := v.(type) {
+	case string:
+		return key, []string{v}, nil
+	case []interface{}:
+		values := make([]string, 0, len(v))
+		for _, e := range v {
+			str, ok := e.(string)
+			if !ok {
+				return key, nil, fmt.Errorf("%s must be a string or a list of strings", key)
+			}
+			values = append(values, str)
+		}
+		if len(values) == 0 {
+			return key, nil, fmt.Errorf("%s must not be empty", key)
+		}
+		return key, values, nil
+	}
+
+	return key, nil, fmt.Errorf("%s must be a string or a list of strings", key)
+}
+
+// policyActionSupported returns true if the action, which may contain wildcards, matches a supported action.
+func policyActionSupported(action string) bool {
+	if action == "*" {
+		return true
+	}
+
+	if !strings.HasPrefix(action, "s3:") {
+		return false
+	}
+
+	for _, supported := range supportedPolicyActions {
+		if matched, _ := path.Match(strings.ToLower(action), strings.ToLower(supported)); matched {
+			return true
+		}
+	}
+
+	return false
+}
+
+func policyStatementName(i int, statement map[string]interface{}) string {
+	if sid := policyStatementSid(statement); sid != "" {
+		return fmt.Sprintf("statement %d (Sid %q)", i+1, sid)
+	}
+	return fmt.Sprintf("statement %d", i+1)
+}
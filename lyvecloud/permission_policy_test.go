@@ -0,0 +1,180 @@
+package lyvecloud
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+)
+
+func TestValidatePermissionPolicy(t *testing.T) {
+	testCases := []struct {
+		Name      string
+		Policy    string
+		Supported bool
+		Expected  []string
+	}{
+		{
+			Name:      "empty",
+			Supported: true,
+		},
+		{
+			Name:      "valid",
+			Policy:    `{"Version":"2012-10-17","Statement":[{"Sid":"read","Effect":"Allow","Action":["s3:GetObject","s3:List*"],"Resource":["arn:aws:s3:::my-bucket","arn:aws:s3:::my-bucket/*"]}]}`,
+			Supported: true,
+		},
+		{
+			Name:      "single statement",
+			Policy:    `{"Version":"2012-10-17","Statement":{"Effect":"Deny","NotAction":"s3:GetObject","Resource":"*"}}`,
+			Supported: true,
+		},
+		{
+			Name:     "invalid JSON",
+			Policy:   `{"Version":`,
+			Expected: []string{"invalid JSON"},
+		},
+		{
+			Name:     "wrong version",
+			Policy:   `{"Version":"2008-10-17","Statement":[{"Effect":"Allow","Action":"s3:GetObject","Resource":"*"}]}`,
+			Expected: []string{`Version 2008-10-17 is not supported`},
+		},
+		{
+			Name:     "no statements",
+			Policy:   `{"Version":"2012-10-17","Statement":[]}`,
+			Expected: []string{"at least one statement"},
+		},
+		{
+			Name:     "principal",
+			Policy:   `{"Version":"2012-10-17","Statement":[{"Sid":"s3","Effect":"Allow","Principal":"*","Action":"s3:GetObject","Resource":"*"}]}`,
+			Expected: []string{`statement 1 (Sid "s3"): Principal is not supported`},
+		},
+		{
+			Name:     "missing action and invalid effect",
+			Policy:   `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":"s3:GetObject","Resource":"*"},{"Effect":"Maybe","Resource":"*"}]}`,
+			Expected: []string{"statement 2: Effect Maybe is not supported", "statement 2: one of Action or NotAction must be set"},
+		},
+		{
+			Name:      "unsupported action",
+			Policy:    `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":"s3:PutBucketWebsite","Resource":"*"}]}`,
+			Supported: true,
+			Expected:  nil,
+		},
+		{
+			Name:      "malformed ARN",
+			Policy:    `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":"s3:GetObject","Resource":["arn:aws:s3::my-bucket/*","arn:aws:s3:::My_Bucket"]}]}`,
+			Supported: true,
+			Expected:  []string{`Resource "arn:aws:s3::my-bucket/*" is not a valid`, `Resource "arn:aws:s3:::My_Bucket" is not a valid`},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			errs := validatePermissionPolicy(testCase.Policy, testCase.Supported)
+
+			if len(errs) != len(testCase.Expected) {
+				t.Fatalf("got %d errors %v, expected %d", len(errs), errs, len(testCase.Expected))
+			}
+
+			for i, err := range errs {
+				if !strings.Contains(err.Error(), testCase.Expected[i]) {
+					t.Errorf("got error %q, expected it to contain %q", err, testCase.Expected[i])
+				}
+			}
+		})
+	}
+}
+
+func TestValidatePermissionPolicyDiag(t *testing.T) {
+	testCases := []struct {
+		Name   string
+		Policy string
+		Errors []string
+	}{
+		{
+			Name:   "valid",
+			Policy: `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":["s3:GetObject","s3:List*"],"Resource":"*"}]}`,
+		},
+		{
+			Name:   "invalid",
+			Policy: `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":"s3:PutBucketWebsite"}]}`,
+			Errors: []string{"statement 1: one of Resource or NotResource must be set"},
+		},
+		{
+			Name:   "unsupported actions",
+			Policy: `{"Version":"2012-10-17","Statement":[{"Sid":"web","Effect":"Allow","Action":["s3:GetObject","s3:PutBucketWebsite","iam:*"],"Resource":"*"}]}`,
+			Errors: []string{`statement 1 (Sid "web"): Action "s3:PutBucketWebsite" is not supported`, `statement 1 (Sid "web"): Action "iam:*" is not supported`},
+		},
+		{
+			Name:   "unsupported not action",
+			Policy: `{"Version":"2012-10-17","Statement":{"Effect":"Deny","NotAction":"s3:PutBucketWebsite","Resource":"*"}}`,
+			Errors: []string{`statement 1: NotAction "s3:PutBucketWebsite" is not supported`},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			var errs []string
+			for _, d := range validatePermissionPolicyDiag(testCase.Policy, cty.GetAttrPath("policy")) {
+				if d.Severity != diag.Error {
+					t.Errorf("got %v diagnostic %q, expected only errors", d.Severity, d.Detail)
+				}
+				errs = append(errs, d.Detail)
+			}
+
+			if !reflect.DeepEqual(errs, testCase.Errors) {
+				t.Errorf("got errors %q, expected %q", errs, testCase.Errors)
+			}
+		})
+	}
+}
+
+func TestSuppressEquivalentPolicyDiffs(t *testing.T) {
+	testCases := []struct {
+		Name     string
+		Old      string
+		New      string
+		Expected bool
+	}{
+		{
+			Name:     "both empty",
+			Expected: true,
+		},
+		{
+			Name: "added policy",
+			New:  `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":"s3:GetObject","Resource":"*"}]}`,
+		},
+		{
+			Name:     "single action as list",
+			Old:      `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":"s3:GetObject","Resource":"*"}]}`,
+			New:      `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":["s3:GetObject"],"Resource":["*"]}]}`,
+			Expected: true,
+		},
+		{
+			Name:     "reordered statements",
+			Old:      `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":"s3:GetObject","Resource":"*"},{"Effect":"Deny","Action":"s3:DeleteObject","Resource":"*"}]}`,
+			New:      `{"Version":"2012-10-17","Statement":[{"Effect":"Deny","Action":"s3:DeleteObject","Resource":"*"},{"Effect":"Allow","Action":"s3:GetObject","Resource":"*"}]}`,
+			Expected: true,
+		},
+		{
+			Name:     "reordered conditions",
+			Old:      `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":"s3:ListBucket","Resource":"*","Condition":{"StringLike":{"s3:prefix":["a/*","b/*"]},"StringEquals":{"s3:delimiter":"/"}}}]}`,
+			New:      `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":"s3:ListBucket","Resource":"*","Condition":{"StringEquals":{"s3:delimiter":["/"]},"StringLike":{"s3:prefix":["b/*","a/*"]}}}]}`,
+			Expected: true,
+		},
+		{
+			Name: "changed action",
+			Old:  `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":"s3:GetObject","Resource":"*"}]}`,
+			New:  `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":"s3:PutObject","Resource":"*"}]}`,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			if got := suppressEquivalentPolicyDiffs("policy", testCase.Old, testCase.New, nil); got != testCase.Expected {
+				t.Errorf("got %t, expected %t", got, testCase.Expected)
+			}
+		})
+	}
+}
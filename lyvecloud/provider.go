@@ -2,11 +2,19 @@ package lyvecloud
 
 import (
 	"context"
+	"encoding/base64"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/credentials/processcreds"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/s3"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
@@ -26,14 +34,14 @@ func Provider() *schema.Provider {
 					Schema: map[string]*schema.Schema{
 						"access_key": {
 							Type:        schema.TypeString,
-							Required:    true,
-							Description: "The access key for S3 API operations.",
+							Optional:    true,
+							Description: "The access key for S3 API operations. Required unless from_account_api or credential_process is set.",
 							DefaultFunc: schema.EnvDefaultFunc("LYVECLOUD_S3_ACCESS_KEY", nil),
 						},
 						"secret_key": {
 							Type:        schema.TypeString,
-							Required:    true,
-							Description: "The secret key for S3 API operations.",
+							Optional:    true,
+							Description: "The secret key for S3 API operations. Required unless from_account_api or credential_process is set.",
 							DefaultFunc: schema.EnvDefaultFunc("LYVECLOUD_S3_SECRET_KEY", nil),
 						},
 						"region": {
@@ -48,6 +56,32 @@ func Provider() *schema.Provider {
 							Description: "Lyve Cloud endpoint URL for S3 API operations.",
 							DefaultFunc: schema.EnvDefaultFunc("LYVECLOUD_S3_ENDPOINT", nil),
 						},
+						"credential_process": {
+							Type:          schema.TypeString,
+							Optional:      true,
+							Description:   "A command printing the access key and secret key as JSON, following the credential_process contract of the AWS CLI, instead of access_key and secret_key.",
+							DefaultFunc:   schema.EnvDefaultFunc("LYVECLOUD_S3_CREDENTIAL_PROCESS", nil),
+							ConflictsWith: []string{"s3.0.access_key", "s3.0.secret_key", "s3.0.from_account_api"},
+						},
+						"from_account_api": {
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Description: "Create temporary S3 credentials through the Account API on the first S3 API call instead of using access_key and secret_key. They are deleted when the provider shuts down.",
+						},
+						"from_account_api_buckets": {
+							Type:          schema.TypeSet,
+							Optional:      true,
+							Elem:          &schema.Schema{Type: schema.TypeString},
+							Set:           schema.HashString,
+							Description:   "The buckets the temporary S3 credentials of from_account_api are limited to. Conflicts with from_account_api_bucket_prefix.",
+							ConflictsWith: []string{"s3.0.from_account_api_bucket_prefix"},
+						},
+						"from_account_api_bucket_prefix": {
+							Type:          schema.TypeString,
+							Optional:      true,
+							Description:   "The bucket name prefix the temporary S3 credentials of from_account_api are limited to. Conflicts with from_account_api_buckets.",
+							ConflictsWith: []string{"s3.0.from_account_api_buckets"},
+						},
 					},
 				},
 			},
@@ -60,37 +94,89 @@ func Provider() *schema.Provider {
 					Schema: map[string]*schema.Schema{
 						"account_id": {
 							Type:        schema.TypeString,
-							Required:    true,
-							Description: "Unique identifier of the Lyve Cloud Account API.",
+							Optional:    true,
+							Description: "Unique identifier of the Lyve Cloud Account API. Conflicts with token.",
 							DefaultFunc: schema.EnvDefaultFunc("LYVECLOUD_ACCOUNT_ID", nil),
 						},
 						"access_key": {
 							Type:        schema.TypeString,
-							Required:    true,
+							Optional:    true,
 							Description: "The access key is generated when you generate Account API credentails.",
 							DefaultFunc: schema.EnvDefaultFunc("LYVECLOUD_ACCOUNT_ACCESS_KEY", nil),
 						},
 						"secret": {
 							Type:        schema.TypeString,
-							Required:    true,
+							Optional:    true,
 							Description: "The secret key is generated when you generate Account API credentials.",
 							DefaultFunc: schema.EnvDefaultFunc("LYVECLOUD_ACCOUNT_SECRET", nil),
 						},
+						"credential_process": {
+							Type:          schema.TypeString,
+							Optional:      true,
+							Description:   "A command printing the access key and secret as JSON, following the credential_process contract of the AWS CLI, instead of access_key and secret.",
+							DefaultFunc:   schema.EnvDefaultFunc("LYVECLOUD_ACCOUNT_CREDENTIAL_PROCESS", nil),
+							ConflictsWith: []string{"account.0.access_key", "account.0.secret", "account.0.token"},
+						},
+						"token": {
+							Type:          schema.TypeString,
+							Optional:      true,
+							Sensitive:     true,
+							Description:   "A bearer token of the Account API obtained beforehand, instead of account_id, access_key and secret.",
+							DefaultFunc:   schema.EnvDefaultFunc("LYVECLOUD_ACCOUNT_TOKEN", nil),
+							ConflictsWith: []string{"account.0.account_id", "account.0.access_key", "account.0.secret", "account.0.credential_process"},
+						},
 					},
 				},
 			},
+			"read_only": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Description: "If true, every create, update and delete fails before making any API call. Reads and data sources work as usual.",
+			},
+			"allowed_bucket_patterns": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Description: "The bucket names resources can target, as globs, or regular expressions enclosed in slashes. All buckets are allowed if unset.",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"allowed_account_ids": {
+				Type:          schema.TypeSet,
+				Optional:      true,
+				Description:   "The account IDs the provider is allowed to operate on. The configuration fails for any other account, or without the account_id of the account block.",
+				Elem:          &schema.Schema{Type: schema.TypeString},
+				Set:           schema.HashString,
+				ConflictsWith: []string{"forbidden_account_ids"},
+			},
+			"forbidden_account_ids": {
+				Type:          schema.TypeSet,
+				Optional:      true,
+				Description:   "The account IDs the provider is not allowed to operate on.",
+				Elem:          &schema.Schema{Type: schema.TypeString},
+				Set:           schema.HashString,
+				ConflictsWith: []string{"allowed_account_ids"},
+			},
 		},
 		ResourcesMap: map[string]*schema.Resource{
-			"lyvecloud_s3_bucket":                           ResourceBucket(),
-			"lyvecloud_s3_object":                           ResourceObject(),
-			"lyvecloud_s3_object_copy":                      ResourceObjectCopy(),
-			"lyvecloud_permission":                          ResourcePermission(),
-			"lyvecloud_service_account":                     ResourceServiceAccount(),
-			"lyvecloud_s3_bucket_object_lock_configuration": ResourceBucketObjectLockConfiguration(),
+			"lyvecloud_s3_bucket":                             ResourceBucket(),
+			"lyvecloud_s3_object":                             ResourceObject(),
+			"lyvecloud_s3_object_copy":                        ResourceObjectCopy(),
+			"lyvecloud_permission":                            ResourcePermission(),
+			"lyvecloud_service_account":                       ResourceServiceAccount(),
+			"lyvecloud_s3_bucket_object_lock_configuration":   ResourceBucketObjectLockConfiguration(),
+			"lyvecloud_service_account_permission_attachment": ResourceServiceAccountPermissionAttachment(),
+			"lyvecloud_permission_bucket_attachment":          ResourcePermissionBucketAttachment(),
 		},
 		DataSourcesMap: map[string]*schema.Resource{
-			"lyvecloud_s3_bucket": DataSourceBucket(),
-			"lyvecloud_s3_object": DataSourceObject(),
+			"lyvecloud_s3_bucket":        DataSourceBucket(),
+			"lyvecloud_s3_object":        DataSourceObject(),
+			"lyvecloud_permission":       DataSourcePermission(),
+			"lyvecloud_permissions":      DataSourcePermissions(),
+			"lyvecloud_policy_document":  DataSourcePolicyDocument(),
+			"lyvecloud_service_account":  DataSourceServiceAccount(),
+			"lyvecloud_service_accounts": DataSourceServiceAccounts(),
+			"lyvecloud_usage_current":    DataSourceUsageCurrent(),
+			"lyvecloud_usage_forecast":   DataSourceUsageForecast(),
+			"lyvecloud_usage_monthly":    DataSourceUsageMonthly(),
 		},
 		ConfigureContextFunc: providerConfigure,
 	}
@@ -98,8 +184,13 @@ func Provider() *schema.Provider {
 
 // createS3Client creates AWS SDK client.
 func createS3Client(region, accessKey, secretKey, endpointUrl string) (*s3.S3, error) {
+	return createS3ClientWithCredentials(region, credentials.NewStaticCredentials(accessKey, secretKey, ""), endpointUrl)
+}
+
+// createS3ClientWithCredentials creates AWS SDK client using the given credentials.
+func createS3ClientWithCredentials(region string, creds *credentials.Credentials, endpointUrl string) (*s3.S3, error) {
 	s3Config := &aws.Config{
-		Credentials:      credentials.NewStaticCredentials(accessKey, secretKey, ""),
+		Credentials:      creds,
 		Endpoint:         aws.String(endpointUrl),
 		Region:           aws.String(region),
 		DisableSSL:       aws.Bool(true),
@@ -113,6 +204,16 @@ func createS3Client(region, accessKey, secretKey, endpointUrl string) (*s3.S3, e
 	return client, nil
 }
 
+// createS3CredentialsFromProcess returns credentials obtained by running command, following the credential_process
+// contract of the AWS CLI. The command is run again when the credentials expire.
+func createS3CredentialsFromProcess(command string) (*credentials.Credentials, error) {
+	creds := processcreds.NewCredentials(command)
+	if _, err := creds.Get(); err != nil {
+		return nil, fmt.Errorf("error running s3 credential_process: %w", err)
+	}
+	return creds, nil
+}
+
 // createAccAPIClient creates Account API v2 client.
 func createAccountAPIClient(accountId, accessKey, secret string) (*AuthData, error) {
 	credentials := AuthRequest{
@@ -128,11 +229,193 @@ func createAccountAPIClient(accountId, accessKey, secret string) (*AuthData, err
 	return accountAPIClient, nil
 }
 
+// createAccountAPIClientFromProcess creates Account API v2 client from the access key and secret obtained by running command,
+// following the credential_process contract of the AWS CLI. The token is renewed before it expires, running the command
+// again if the credentials expired.
+func createAccountAPIClientFromProcess(accountId, command string) (*AuthData, error) {
+	creds := processcreds.NewCredentials(command)
+
+	authenticate := func() (*AuthData, error) {
+		value, err := creds.Get()
+		if err != nil {
+			return nil, fmt.Errorf("error running account credential_process: %w", err)
+		}
+
+		return createAccountAPIClient(accountId, value.AccessKeyID, value.SecretAccessKey)
+	}
+
+	accountAPIClient, err := authenticate()
+	if err != nil {
+		return nil, err
+	}
+	accountAPIClient.renewal = newTokenRenewal(accountAPIClient, authenticate, time.Now())
+
+	return accountAPIClient, nil
+}
+
+// validateAccountId returns an error if accountId isn't one of allowed, when set, or is one of forbidden.
+func validateAccountId(accountId string, allowed, forbidden []string) error {
+	if len(allowed) > 0 && !stringInSlice(accountId, allowed) {
+		return fmt.Errorf("account ID not allowed: %s, allowed_account_ids is %s", accountId, strings.Join(allowed, ", "))
+	}
+
+	if stringInSlice(accountId, forbidden) {
+		return fmt.Errorf("account ID forbidden: %s is in forbidden_account_ids", accountId)
+	}
+
+	return nil
+}
+
+// expandStringSet returns the sorted strings of a set.
+func expandStringSet(set *schema.Set) []string {
+	list := []string{}
+	for _, v := range set.List() {
+		list = append(list, v.(string))
+	}
+	sort.Strings(list)
+	return list
+}
+
+// accountTokenExpiryWarning is how close to its expiry a pre-obtained Account API token is reported.
+const accountTokenExpiryWarning = 15 * time.Minute
+
+// createAccountAPIClientFromToken creates Account API v2 client from a pre-obtained token.
+// The expiry of JWT tokens is checked, other tokens are used as is.
+func createAccountAPIClientFromToken(token string, now time.Time) (*AuthData, diag.Diagnostics) {
+	accountAPIClient := &AuthData{Token: token}
+
+	expiresAt, ok := accountTokenExpiration(token)
+	if !ok {
+		log.Printf("[DEBUG] Expiry of the Account API token is unknown")
+		return accountAPIClient, nil
+	}
+
+	if !now.Before(expiresAt) {
+		return nil, diag.Errorf("the Account API token expired at %s, obtain a new token", expiresAt.UTC().Format(time.RFC3339))
+	}
+
+	remaining := expiresAt.Sub(now)
+	accountAPIClient.ExpirationSec = strconv.Itoa(int(remaining.Seconds()))
+
+	if remaining < accountTokenExpiryWarning {
+		return accountAPIClient, diag.Diagnostics{{
+			Severity: diag.Warning,
+			Summary:  "Account API token expires soon",
+			Detail:   fmt.Sprintf("The Account API token expires at %s, in %s. Requests made after that will fail.", expiresAt.UTC().Format(time.RFC3339), remaining.Round(time.Second)),
+		}}
+	}
+
+	return accountAPIClient, nil
+}
+
+// accountTokenExpiration returns the expiry (exp claim) of a JWT token, without verifying it.
+func accountTokenExpiration(token string) (time.Time, bool) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return time.Time{}, false
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	var claims struct {
+		Exp *json.Number `json:"exp"`
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil || claims.Exp == nil {
+		return time.Time{}, false
+	}
+
+	exp, err := claims.Exp.Float64()
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	return time.Unix(int64(exp), 0), true
+}
+
 func providerConfigure(ctx context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
 	var s3Client *s3.S3
 	var accountAPIClient *AuthData
+	var accountId string
+	var diags diag.Diagnostics
 	var err error
 
+	allowedAccountIds := expandStringSet(d.Get("allowed_account_ids").(*schema.Set))
+	forbiddenAccountIds := expandStringSet(d.Get("forbidden_account_ids").(*schema.Set))
+
+	if accountAPI, ok := d.Get("account").([]interface{}); ok && len(accountAPI) > 0 && accountAPI[0] != nil {
+		accountAPIAttr := accountAPI[0].(map[string]interface{})
+
+		var accessKey, secret string
+
+		// the arguments can't use ExactlyOneOf, which ignores values set through environment variables.
+		if v, ok := accountAPIAttr["token"].(string); ok && v != "" {
+			for _, key := range []string{"account_id", "access_key", "secret", "credential_process"} {
+				if accountAPIAttr[key].(string) != "" {
+					return nil, diag.FromErr(fmt.Errorf("only one of token or account_id with access_key and secret or credential_process can be set, but both token and %s are set", key))
+				}
+			}
+
+			accountAPIClient, diags = createAccountAPIClientFromToken(v, time.Now())
+			if diags.HasError() {
+				return nil, diags
+			}
+		} else {
+			if v, ok := accountAPIAttr["account_id"].(string); ok && v != "" {
+				accountId = v
+			} else {
+				return nil, diag.FromErr(errors.New("account_id must be set and contain a non-empty value, or token must be set"))
+			}
+
+			// the account is checked before authenticating, and before any API call.
+			if err := validateAccountId(accountId, allowedAccountIds, forbiddenAccountIds); err != nil {
+				return nil, diag.FromErr(err)
+			}
+
+			if v, ok := accountAPIAttr["credential_process"].(string); ok && v != "" {
+				if accountAPIAttr["access_key"].(string) != "" || accountAPIAttr["secret"].(string) != "" {
+					return nil, diag.FromErr(errors.New("access_key and secret can't be set with credential_process"))
+				}
+
+				accountAPIClient, err = createAccountAPIClientFromProcess(accountId, v)
+				if err != nil {
+					return nil, diag.FromErr(err)
+				}
+			} else {
+				if v, ok := accountAPIAttr["access_key"].(string); ok && v != "" {
+					accessKey = v
+				} else {
+					return nil, diag.FromErr(errors.New("access_key must be set and contain a non-empty value, or token or credential_process must be set"))
+				}
+
+				if v, ok := accountAPIAttr["secret"].(string); ok && v != "" {
+					secret = v
+				} else {
+					return nil, diag.FromErr(errors.New("secret must be set and contain a non-empty value, or token or credential_process must be set"))
+				}
+
+				accountAPIClient, err = createAccountAPIClient(accountId, accessKey, secret)
+				if err != nil {
+					return nil, diag.FromErr(err)
+				}
+			}
+		}
+	}
+
+	if accountId == "" && len(allowedAccountIds) > 0 {
+		return nil, diag.FromErr(errors.New("allowed_account_ids requires the account_id of the account block, the account can't be determined without it"))
+	}
+
+	if accountId == "" && len(forbiddenAccountIds) > 0 {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Warning,
+			Summary:  "Account ID unknown",
+			Detail:   "The account ID can't be determined without the account_id of the account block, forbidden_account_ids isn't checked.",
+		})
+	}
+
 	if s3, ok := d.Get("s3").([]interface{}); ok && len(s3) > 0 && s3[0] != nil {
 		s3Attr := s3[0].(map[string]interface{})
 
@@ -144,58 +427,94 @@ func providerConfigure(ctx context.Context, d *schema.ResourceData) (interface{}
 			return nil, diag.FromErr(errors.New("region must be set and contain a non-empty value"))
 		}
 
-		if v, ok := s3Attr["access_key"].(string); ok && v != "" {
-			accessKey = v
-		} else {
-			return nil, diag.FromErr(errors.New("access_key must be set and contain a non-empty value"))
-		}
-
-		if v, ok := s3Attr["secret_key"].(string); ok && v != "" {
-			secretKey = v
-		} else {
-			return nil, diag.FromErr(errors.New("secret_key must be set and contain a non-empty value"))
-		}
-
 		if v, ok := s3Attr["endpoint_url"].(string); ok && v != "" {
 			endpointUrl = v
 		} else {
 			return nil, diag.FromErr(errors.New("endpoint_url must be set and contain a non-empty value"))
 		}
 
-		s3Client, err = createS3Client(region, accessKey, secretKey, endpointUrl)
-		if err != nil {
-			return nil, diag.FromErr(err)
-		}
-	}
+		var creds *credentials.Credentials
 
-	if accountAPI, ok := d.Get("account").([]interface{}); ok && len(accountAPI) > 0 && accountAPI[0] != nil {
-		accountAPIAttr := accountAPI[0].(map[string]interface{})
+		if v, ok := s3Attr["from_account_api"].(bool); ok && v {
+			if s3Attr["access_key"].(string) != "" || s3Attr["secret_key"].(string) != "" || s3Attr["credential_process"].(string) != "" {
+				return nil, diag.FromErr(errors.New("access_key, secret_key and credential_process can't be set with from_account_api"))
+			}
+
+			if accountAPIClient == nil {
+				return nil, diag.FromErr(errors.New("the account block must be set to use from_account_api"))
+			}
 
-		var accountId, accessKey, secret string
+			if d.Get("read_only").(bool) {
+				return nil, diag.FromErr(errors.New("from_account_api can't be used with read_only, it creates a service account and a permission"))
+			}
 
-		if v, ok := accountAPIAttr["account_id"].(string); ok && v != "" {
-			accountId = v
+			scope := &Permission{}
+			if buckets := expandStringSet(s3Attr["from_account_api_buckets"].(*schema.Set)); len(buckets) > 0 {
+				scope.Type = "bucket-names"
+				scope.Buckets = buckets
+			} else if prefix := s3Attr["from_account_api_bucket_prefix"].(string); prefix != "" {
+				scope.Type = "bucket-prefix"
+				scope.Prefix = prefix
+			} else {
+				return nil, diag.FromErr(errors.New("from_account_api requires from_account_api_buckets or from_account_api_bucket_prefix, which limit the buckets of the temporary S3 credentials"))
+			}
+
+			// the credentials are created on the first S3 API call, configuring the provider creates none.
+			creds = credentials.NewCredentials(&bootstrapCredentialsProvider{
+				conn:     accountAPIClient,
+				region:   region,
+				endpoint: endpointUrl,
+				scope:    scope,
+			})
+		} else if s3Attr["from_account_api_buckets"].(*schema.Set).Len() > 0 || s3Attr["from_account_api_bucket_prefix"].(string) != "" {
+			return nil, diag.FromErr(errors.New("from_account_api_buckets and from_account_api_bucket_prefix can only be set with from_account_api"))
+		} else if v, ok := s3Attr["credential_process"].(string); ok && v != "" {
+			if s3Attr["access_key"].(string) != "" || s3Attr["secret_key"].(string) != "" {
+				return nil, diag.FromErr(errors.New("access_key and secret_key can't be set with credential_process"))
+			}
+
+			creds, err = createS3CredentialsFromProcess(v)
+			if err != nil {
+				return nil, diag.FromErr(err)
+			}
 		} else {
-			return nil, diag.FromErr(errors.New("account_id must be set and contain a non-empty value"))
+			if v, ok := s3Attr["access_key"].(string); ok && v != "" {
+				accessKey = v
+			} else {
+				return nil, diag.FromErr(errors.New("access_key must be set and contain a non-empty value"))
+			}
+
+			if v, ok := s3Attr["secret_key"].(string); ok && v != "" {
+				secretKey = v
+			} else {
+				return nil, diag.FromErr(errors.New("secret_key must be set and contain a non-empty value"))
+			}
+			creds = credentials.NewStaticCredentials(accessKey, secretKey, "")
 		}
 
-		if v, ok := accountAPIAttr["access_key"].(string); ok && v != "" {
-			accessKey = v
-		} else {
-			return nil, diag.FromErr(errors.New("access_key must be set and contain a non-empty value"))
+		s3Client, err = createS3ClientWithCredentials(region, creds, endpointUrl)
+		if err != nil {
+			return nil, diag.FromErr(err)
 		}
+	}
 
-		if v, ok := accountAPIAttr["secret"].(string); ok && v != "" {
-			secret = v
-		} else {
-			return nil, diag.FromErr(errors.New("secret must be set and contain a non-empty value"))
+	var allowedBucketPatterns []bucketPattern
+	if v, ok := d.GetOk("allowed_bucket_patterns"); ok {
+		patterns, err := convertBucketsList(v.([]interface{}))
+		if err != nil {
+			return nil, diag.FromErr(err)
 		}
 
-		accountAPIClient, err = createAccountAPIClient(accountId, accessKey, secret)
+		allowedBucketPatterns, err = expandBucketPatterns(patterns)
 		if err != nil {
 			return nil, diag.FromErr(err)
 		}
 	}
 
-	return Client{S3Client: s3Client, AccountAPIClient: accountAPIClient}, nil
+	return Client{
+		S3Client:              s3Client,
+		AccountAPIClient:      accountAPIClient,
+		ReadOnly:              d.Get("read_only").(bool),
+		AllowedBucketPatterns: allowedBucketPatterns,
+	}, diags
 }
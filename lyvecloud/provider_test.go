@@ -1,10 +1,28 @@
 package lyvecloud
 
 import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
 	"os"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/hashicorp/go-cty/cty"
+	ctyjson "github.com/hashicorp/go-cty/cty/json"
+	"github.com/hashicorp/go-cty/cty/msgpack"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 )
 
 var testAccProviders map[string]func() (*schema.Provider, error)
@@ -29,6 +47,390 @@ func TestProvider_impl(t *testing.T) {
 	var _ *schema.Provider = Provider()
 }
 
+// TestProviderRegistersConstructors checks that every DataSource* and Resource* constructor of the package
+// is registered in the DataSourcesMap or ResourcesMap of the provider.
+func TestProviderRegistersConstructors(t *testing.T) {
+	fset := token.NewFileSet()
+	packages, err := parser.ParseDir(fset, ".", func(info fs.FileInfo) bool {
+		return !strings.HasSuffix(info.Name(), "_test.go")
+	}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	constructors := map[string]string{}
+	registered := map[string]bool{}
+	for _, file := range packages["lyvecloud"].Files {
+		ast.Inspect(file, func(node ast.Node) bool {
+			switch node := node.(type) {
+			case *ast.FuncDecl:
+				for _, prefix := range []string{"DataSource", "Resource"} {
+					if node.Recv == nil && strings.HasPrefix(node.Name.Name, prefix) && node.Type.Params.NumFields() == 0 {
+						constructors[node.Name.Name] = prefix + "sMap"
+					}
+				}
+			case *ast.KeyValueExpr:
+				key, ok := node.Key.(*ast.Ident)
+				if !ok || (key.Name != "DataSourcesMap" && key.Name != "ResourcesMap") {
+					return true
+				}
+
+				ast.Inspect(node.Value, func(node ast.Node) bool {
+					if call, ok := node.(*ast.CallExpr); ok {
+						if fun, ok := call.Fun.(*ast.Ident); ok {
+							registered[key.Name+"/"+fun.Name] = true
+						}
+					}
+					return true
+				})
+			}
+			return true
+		})
+	}
+
+	if len(constructors) == 0 {
+		t.Fatal("no constructors found")
+	}
+
+	for name, mapName := range constructors {
+		if !registered[mapName+"/"+name] {
+			t.Errorf("%s is not registered in the %s of the provider", name, mapName)
+		}
+	}
+}
+
+func TestProviderReadOnly(t *testing.T) {
+	// the clients can't make any API call, which would panic.
+	meta := Client{S3Client: &s3.S3{}, AccountAPIClient: &AuthData{}, ReadOnly: true}
+
+	for name, r := range Provider().ResourcesMap {
+		t.Run(name, func(t *testing.T) {
+			d := r.TestResourceData()
+			d.SetId("test")
+
+			var errs []error
+			if r.Create != nil {
+				errs = append(errs, r.Create(d, meta))
+			}
+			if r.CreateContext != nil {
+				errs = append(errs, diagError(r.CreateContext(context.Background(), d, meta)))
+			}
+			if r.Update != nil {
+				errs = append(errs, r.Update(d, meta))
+			}
+			if r.UpdateContext != nil {
+				errs = append(errs, diagError(r.UpdateContext(context.Background(), d, meta)))
+			}
+			if r.Delete != nil {
+				errs = append(errs, r.Delete(d, meta))
+			}
+			if r.DeleteContext != nil {
+				errs = append(errs, diagError(r.DeleteContext(context.Background(), d, meta)))
+			}
+
+			for _, err := range errs {
+				if err == nil || !strings.Contains(err.Error(), "read-only") {
+					t.Errorf("expected read-only error, got %v", err)
+				}
+			}
+		})
+	}
+}
+
+func diagError(diags diag.Diagnostics) error {
+	for _, d := range diags {
+		if d.Severity == diag.Error {
+			return fmt.Errorf("%s", d.Summary)
+		}
+	}
+	return nil
+}
+
+func TestCreateAccountAPIClientFromToken(t *testing.T) {
+	now := time.Date(2023, time.March, 31, 12, 0, 0, 0, time.UTC)
+
+	jwt := func(payload string) string {
+		encode := base64.RawURLEncoding.EncodeToString
+		return encode([]byte(`{"alg":"HS256","typ":"JWT"}`)) + "." + encode([]byte(payload)) + ".signature"
+	}
+
+	testCases := []struct {
+		Name          string
+		Token         string
+		ExpirationSec string
+		ExpectWarning bool
+		ExpectError   bool
+	}{
+		{
+			Name:  "opaque token",
+			Token: "opaque-token",
+		},
+		{
+			Name:  "JWT without expiry",
+			Token: jwt(`{"sub":"account"}`),
+		},
+		{
+			Name:          "valid JWT",
+			Token:         jwt(fmt.Sprintf(`{"sub":"account","exp":%d}`, now.Add(time.Hour).Unix())),
+			ExpirationSec: "3600",
+		},
+		{
+			Name:          "JWT expiring soon",
+			Token:         jwt(fmt.Sprintf(`{"sub":"account","exp":%d}`, now.Add(time.Minute).Unix())),
+			ExpirationSec: "60",
+			ExpectWarning: true,
+		},
+		{
+			Name:        "expired JWT",
+			Token:       jwt(fmt.Sprintf(`{"sub":"account","exp":%d}`, now.Add(-time.Minute).Unix())),
+			ExpectError: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			client, diags := createAccountAPIClientFromToken(testCase.Token, now)
+
+			if testCase.ExpectError {
+				if !diags.HasError() {
+					t.Fatal("expected error")
+				}
+				return
+			}
+
+			if diags.HasError() {
+				t.Fatalf("unexpected error: %v", diags)
+			}
+
+			if got := len(diags) > 0; got != testCase.ExpectWarning {
+				t.Errorf("got warning %t, expected %t", got, testCase.ExpectWarning)
+			}
+
+			if client.Token != testCase.Token {
+				t.Errorf("got token %q, expected %q", client.Token, testCase.Token)
+			}
+
+			if client.ExpirationSec != testCase.ExpirationSec {
+				t.Errorf("got ExpirationSec %q, expected %q", client.ExpirationSec, testCase.ExpirationSec)
+			}
+		})
+	}
+}
+
+// TestHelperProcess isn't a real test, it stands for the command of credential_process, printing
+// LYVECLOUD_HELPER_OUTPUT and exiting with LYVECLOUD_HELPER_EXIT_CODE.
+func TestHelperProcess(t *testing.T) {
+	if os.Getenv("LYVECLOUD_WANT_HELPER_PROCESS") != "1" {
+		return
+	}
+
+	fmt.Fprint(os.Stdout, os.Getenv("LYVECLOUD_HELPER_OUTPUT"))
+
+	code, _ := strconv.Atoi(os.Getenv("LYVECLOUD_HELPER_EXIT_CODE"))
+	os.Exit(code)
+}
+
+// helperProcessCommand returns a credential_process command running TestHelperProcess, which prints output and
+// exits with code.
+func helperProcessCommand(t *testing.T, output string, code int) string {
+	t.Setenv("LYVECLOUD_WANT_HELPER_PROCESS", "1")
+	t.Setenv("LYVECLOUD_HELPER_OUTPUT", output)
+	t.Setenv("LYVECLOUD_HELPER_EXIT_CODE", strconv.Itoa(code))
+
+	return fmt.Sprintf(`"%s" -test.run=TestHelperProcess`, os.Args[0])
+}
+
+func TestCreateS3CredentialsFromProcess(t *testing.T) {
+	testCases := []struct {
+		Name        string
+		Output      string
+		ExitCode    int
+		ExpectError bool
+	}{
+		{
+			Name:   "valid output",
+			Output: `{"Version":1,"AccessKeyId":"access-key","SecretAccessKey":"secret-key","Expiration":"2100-01-01T00:00:00Z"}`,
+		},
+		{
+			Name:        "unsupported version",
+			Output:      `{"Version":2,"AccessKeyId":"access-key","SecretAccessKey":"secret-key"}`,
+			ExpectError: true,
+		},
+		{
+			Name:        "failing command",
+			ExitCode:    1,
+			ExpectError: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			creds, err := createS3CredentialsFromProcess(helperProcessCommand(t, testCase.Output, testCase.ExitCode))
+
+			if testCase.ExpectError {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			value, err := creds.Get()
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if value.AccessKeyID != "access-key" || value.SecretAccessKey != "secret-key" {
+				t.Errorf("got %q/%q, expected access-key/secret-key", value.AccessKeyID, value.SecretAccessKey)
+			}
+		})
+	}
+}
+
+func TestProviderAccountTokenConflict(t *testing.T) {
+	for _, key := range []string{"account_id", "access_key", "secret", "credential_process"} {
+		t.Run(key, func(t *testing.T) {
+			raw := map[string]interface{}{
+				"account": []interface{}{map[string]interface{}{"token": "token", key: "value"}},
+			}
+
+			err := diagError(Provider().Configure(context.Background(), terraform.NewResourceConfigRaw(raw)))
+			expected := "but both token and " + key + " are set"
+			if err == nil || !strings.Contains(err.Error(), expected) || !strings.Contains(err.Error(), "credential_process") {
+				t.Errorf("got error %v, expected it to contain %q and mention credential_process", err, expected)
+			}
+		})
+	}
+}
+
+func TestValidateAccountId(t *testing.T) {
+	testCases := []struct {
+		Name        string
+		AccountId   string
+		Allowed     []string
+		Forbidden   []string
+		ExpectError bool
+	}{
+		{
+			Name:      "no restriction",
+			AccountId: "prod",
+		},
+		{
+			Name:      "allowed",
+			AccountId: "prod",
+			Allowed:   []string{"dev", "prod"},
+		},
+		{
+			Name:        "not allowed",
+			AccountId:   "prod",
+			Allowed:     []string{"dev"},
+			ExpectError: true,
+		},
+		{
+			Name:      "not forbidden",
+			AccountId: "dev",
+			Forbidden: []string{"prod"},
+		},
+		{
+			Name:        "forbidden",
+			AccountId:   "prod",
+			Forbidden:   []string{"prod"},
+			ExpectError: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			err := validateAccountId(testCase.AccountId, testCase.Allowed, testCase.Forbidden)
+
+			if testCase.ExpectError && err == nil {
+				t.Error("expected error")
+			}
+
+			if !testCase.ExpectError && err != nil {
+				t.Errorf("unexpected error: %s", err)
+			}
+		})
+	}
+}
+
+func TestProviderAccountIds(t *testing.T) {
+	testCases := []struct {
+		Name          string
+		Raw           map[string]interface{}
+		ExpectedError string
+		ExpectWarning bool
+	}{
+		{
+			Name: "account not allowed",
+			Raw: map[string]interface{}{
+				"allowed_account_ids": []interface{}{"dev"},
+				"account":             []interface{}{map[string]interface{}{"account_id": "prod", "access_key": "access-key", "secret": "secret"}},
+			},
+			ExpectedError: "account ID not allowed: prod",
+		},
+		{
+			Name: "account forbidden",
+			Raw: map[string]interface{}{
+				"forbidden_account_ids": []interface{}{"prod"},
+				"account":               []interface{}{map[string]interface{}{"account_id": "prod", "access_key": "access-key", "secret": "secret"}},
+			},
+			ExpectedError: "account ID forbidden: prod",
+		},
+		{
+			Name: "allowed with unknown account",
+			Raw: map[string]interface{}{
+				"allowed_account_ids": []interface{}{"dev"},
+				"account":             []interface{}{map[string]interface{}{"token": "token"}},
+			},
+			ExpectedError: "allowed_account_ids requires the account_id of the account block",
+		},
+		{
+			Name: "allowed without account block",
+			Raw: map[string]interface{}{
+				"allowed_account_ids": []interface{}{"dev"},
+				"s3":                  []interface{}{map[string]interface{}{"region": "us-east-1", "endpoint_url": "s3.us-east-1.lyvecloud.seagate.com", "access_key": "access-key", "secret_key": "secret-key"}},
+			},
+			ExpectedError: "allowed_account_ids requires the account_id of the account block",
+		},
+		{
+			Name: "forbidden with unknown account",
+			Raw: map[string]interface{}{
+				"forbidden_account_ids": []interface{}{"prod"},
+				"account":               []interface{}{map[string]interface{}{"token": "token"}},
+			},
+			ExpectWarning: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			api := newFakeAccountAPI(t, nil)
+
+			diags := Provider().Configure(context.Background(), terraform.NewResourceConfigRaw(testCase.Raw))
+			err := diagError(diags)
+			if testCase.ExpectedError == "" && err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if testCase.ExpectedError != "" && (err == nil || !strings.Contains(err.Error(), testCase.ExpectedError)) {
+				t.Fatalf("got error %v, expected it to contain %q", err, testCase.ExpectedError)
+			}
+
+			if got := len(diags) > 0 && !diags.HasError(); got != testCase.ExpectWarning {
+				t.Errorf("got warning %t, expected %t", got, testCase.ExpectWarning)
+			}
+
+			// the account is checked before authenticating.
+			if len(api.requests) > 0 {
+				t.Errorf("got Account API requests %v, expected none", api.requests)
+			}
+		})
+	}
+}
+
 func testAccPreCheck(t *testing.T) {
 	ok := os.Getenv("TF_ACC") == "1"
 
@@ -57,3 +459,117 @@ func testAccPreCheck(t *testing.T) {
 		panic("you must to set env variables for integration tests!")
 	}
 }
+
+// upgradeBaselineState checks that rawState, as written by the provider before the schema versioning, matches the
+// version 0 schema v0 and upgrades it through the provider server as Terraform does, returning the upgraded state.
+func upgradeBaselineState(t *testing.T, typeName string, v0 *schema.Resource, rawState string) cty.Value {
+	t.Helper()
+
+	v0Type := v0.CoreConfigSchema().ImpliedType()
+	if _, err := ctyjson.Unmarshal([]byte(rawState), v0Type); err != nil {
+		t.Fatalf("baseline state doesn't match the version 0 schema: %s", err)
+	}
+
+	var attributes map[string]interface{}
+	if err := json.Unmarshal([]byte(rawState), &attributes); err != nil {
+		t.Fatal(err)
+	}
+	for name := range v0Type.AttributeTypes() {
+		if _, ok := attributes[name]; !ok {
+			t.Errorf("version 0 schema has %s, which isn't in the baseline state", name)
+		}
+	}
+
+	provider := Provider()
+	resp, err := schema.NewGRPCProviderServer(provider).UpgradeResourceState(context.Background(), &tfprotov5.UpgradeResourceStateRequest{
+		TypeName: typeName,
+		Version:  0,
+		RawState: &tfprotov5.RawState{JSON: []byte(rawState)},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	for _, d := range resp.Diagnostics {
+		t.Errorf("unexpected diagnostic: %s: %s", d.Summary, d.Detail)
+	}
+	if t.Failed() {
+		t.FailNow()
+	}
+
+	state, err := msgpack.Unmarshal(resp.UpgradedState.MsgPack, provider.ResourcesMap[typeName].CoreConfigSchema().ImpliedType())
+	if err != nil {
+		t.Fatalf("error decoding upgraded state: %s", err)
+	}
+
+	return state
+}
+
+// planUpgradedState plans an upgraded state against the configuration it was written from, whose arguments are
+// the ones of the version 0 schema, and fails the test if the plan shows a change.
+func planUpgradedState(t *testing.T, typeName string, v0 *schema.Resource, state cty.Value) {
+	t.Helper()
+
+	provider := Provider()
+	block := provider.ResourcesMap[typeName].CoreConfigSchema()
+	v0Block := v0.CoreConfigSchema()
+
+	config := map[string]cty.Value{}
+	proposed := map[string]cty.Value{}
+	for name, attribute := range block.Attributes {
+		config[name] = cty.NullVal(attribute.Type)
+		if v0Attribute, ok := v0Block.Attributes[name]; ok && (v0Attribute.Optional || v0Attribute.Required) {
+			config[name] = state.GetAttr(name)
+		}
+
+		// as Terraform does, computed attributes left out of the configuration keep their prior value.
+		proposed[name] = config[name]
+		if config[name].IsNull() && attribute.Computed {
+			proposed[name] = state.GetAttr(name)
+		}
+	}
+	for name := range block.BlockTypes {
+		config[name] = state.GetAttr(name)
+		proposed[name] = state.GetAttr(name)
+	}
+
+	objectType := block.ImpliedType()
+	encode := func(v cty.Value) *tfprotov5.DynamicValue {
+		b, err := msgpack.Marshal(v, objectType)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return &tfprotov5.DynamicValue{MsgPack: b}
+	}
+
+	resp, err := schema.NewGRPCProviderServer(provider).PlanResourceChange(context.Background(), &tfprotov5.PlanResourceChangeRequest{
+		TypeName:         typeName,
+		PriorState:       encode(state),
+		ProposedNewState: encode(cty.ObjectVal(proposed)),
+		Config:           encode(cty.ObjectVal(config)),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	for _, d := range resp.Diagnostics {
+		t.Errorf("unexpected diagnostic: %s: %s", d.Summary, d.Detail)
+	}
+	if t.Failed() {
+		t.FailNow()
+	}
+
+	planned, err := msgpack.Unmarshal(resp.PlannedState.MsgPack, objectType)
+	if err != nil {
+		t.Fatalf("error decoding planned state: %s", err)
+	}
+
+	for name := range objectType.AttributeTypes() {
+		if got, prior := planned.GetAttr(name), state.GetAttr(name); !got.RawEquals(prior) {
+			t.Errorf("upgraded state plans a change of %s from %#v to %#v", name, prior, got)
+		}
+	}
+	if len(resp.RequiresReplace) > 0 {
+		t.Errorf("upgraded state plans a replacement on %v", resp.RequiresReplace)
+	}
+}
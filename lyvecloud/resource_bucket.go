@@ -31,8 +31,11 @@ func ResourceBucket() *schema.Resource {
 		DeleteContext: resourceBucketDelete,
 
 		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
+			StateContext: importStateAllowedBucket,
 		},
+
+		CustomizeDiff: customizeDiffAllowedBucket,
+
 		Schema: map[string]*schema.Schema{
 			"bucket": {
 				Type:          schema.TypeString,
@@ -78,6 +81,10 @@ func resourceBucketCreate(d *schema.ResourceData, meta interface{}) error {
 		return fmt.Errorf("credentials for S3 operations are missing")
 	}
 
+	if err := CheckReadOnly(meta.(Client), "create bucket"); err != nil {
+		return err
+	}
+
 	conn := *meta.(Client).S3Client
 	bucket := d.Get("bucket").(string)
 	if v, ok := d.GetOk("bucket"); ok {
@@ -88,6 +95,11 @@ func resourceBucketCreate(d *schema.ResourceData, meta interface{}) error {
 		bucket = resource.UniqueId()
 	}
 
+	// generated names are only known now.
+	if err := CheckAllowedBucket(meta.(Client), bucket); err != nil {
+		return err
+	}
+
 	req := &s3.CreateBucketInput{
 		Bucket:                     aws.String(bucket),
 		ObjectLockEnabledForBucket: aws.Bool(d.Get("object_lock_enabled").(bool)),
@@ -124,6 +136,10 @@ func resourceBucketUpdate(d *schema.ResourceData, meta interface{}) error {
 		return fmt.Errorf("credentials for S3 operations are missing")
 	}
 
+	if err := CheckReadOnly(meta.(Client), "update bucket"); err != nil {
+		return err
+	}
+
 	conn := *meta.(Client).S3Client
 
 	if d.HasChange("tags") {
@@ -290,6 +306,15 @@ func resourceBucketDelete(ctx context.Context, d *schema.ResourceData, meta inte
 		return diag.FromErr(fmt.Errorf("credentials for S3 operations are missing"))
 	}
 
+	if err := CheckReadOnly(meta.(Client), "delete bucket"); err != nil {
+		return diag.FromErr(err)
+	}
+
+	// CustomizeDiff doesn't run on destroy.
+	if err := CheckAllowedBucket(meta.(Client), d.Id()); err != nil {
+		return diag.FromErr(err)
+	}
+
 	conn := *meta.(Client).S3Client
 
 	_, err := conn.DeleteBucketWithContext(ctx, &s3.DeleteBucketInput{
@@ -21,9 +21,11 @@ func ResourceBucketObjectLockConfiguration() *schema.Resource {
 		UpdateContext: resourceBucketObjectLockConfigurationUpdate,
 		DeleteContext: resourceBucketObjectLockConfigurationDelete,
 		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
+			StateContext: importStateAllowedBucket,
 		},
 
+		CustomizeDiff: customizeDiffAllowedBucket,
+
 		Schema: map[string]*schema.Schema{
 			"bucket": {
 				Type:         schema.TypeString,
@@ -80,6 +82,10 @@ func resourceBucketObjectLockConfigurationCreate(ctx context.Context, d *schema.
 		return diag.FromErr(fmt.Errorf("credentials for S3 operations are missing"))
 	}
 
+	if err := CheckReadOnly(meta.(Client), "create object lock configuration"); err != nil {
+		return diag.FromErr(err)
+	}
+
 	conn := *meta.(Client).S3Client
 
 	bucket := d.Get("bucket").(string)
@@ -158,6 +164,10 @@ func resourceBucketObjectLockConfigurationUpdate(ctx context.Context, d *schema.
 		return diag.FromErr(fmt.Errorf("credentials for S3 operations are missing"))
 	}
 
+	if err := CheckReadOnly(meta.(Client), "update object lock configuration"); err != nil {
+		return diag.FromErr(err)
+	}
+
 	conn := *meta.(Client).S3Client
 
 	bucket := d.Id()
@@ -190,10 +200,19 @@ func resourceBucketObjectLockConfigurationDelete(ctx context.Context, d *schema.
 		return diag.FromErr(fmt.Errorf("credentials for S3 operations are missing"))
 	}
 
+	if err := CheckReadOnly(meta.(Client), "delete object lock configuration"); err != nil {
+		return diag.FromErr(err)
+	}
+
 	conn := *meta.(Client).S3Client
 
 	bucket := d.Id()
 
+	// CustomizeDiff doesn't run on destroy.
+	if err := CheckAllowedBucket(meta.(Client), bucket); err != nil {
+		return diag.FromErr(err)
+	}
+
 	input := &s3.PutObjectLockConfigurationInput{
 		Bucket: aws.String(bucket),
 		ObjectLockConfiguration: &s3.ObjectLockConfiguration{
@@ -42,6 +42,7 @@ func ResourceObject() *schema.Resource {
 		},
 
 		CustomizeDiff: customdiff.Sequence(
+			customizeDiffAllowedBucket,
 			resourceObjectCustomizeDiff,
 		),
 
@@ -138,6 +139,10 @@ func ResourceObject() *schema.Resource {
 }
 
 func resourceObjectCreate(d *schema.ResourceData, meta interface{}) error {
+	if err := CheckReadOnly(meta.(Client), "create object"); err != nil {
+		return err
+	}
+
 	return resourceObjectUpload(d, meta)
 }
 
@@ -237,6 +242,10 @@ func resourceObjectUpdate(d *schema.ResourceData, meta interface{}) error {
 		return fmt.Errorf("credentials for S3 operations are missing")
 	}
 
+	if err := CheckReadOnly(meta.(Client), "update object"); err != nil {
+		return err
+	}
+
 	if hasObjectContentChanges(d) {
 		return resourceObjectUpload(d, meta)
 	}
@@ -288,10 +297,20 @@ func resourceObjectDelete(d *schema.ResourceData, meta interface{}) error {
 		return fmt.Errorf("credentials for S3 operations are missing")
 	}
 
+	if err := CheckReadOnly(meta.(Client), "delete object"); err != nil {
+		return err
+	}
+
 	conn := meta.(Client).S3Client
 
 	bucket := d.Get("bucket").(string)
 	key := d.Get("key").(string)
+
+	// CustomizeDiff doesn't run on destroy.
+	if err := CheckAllowedBucket(meta.(Client), bucket); err != nil {
+		return err
+	}
+
 	// We are effectively ignoring all leading '/'s in the key name and
 	// treating multiple '/'s as a single '/' as aws.Config.DisableRestProtocolURICleaning is false
 	key = strings.TrimLeft(key, "/")
@@ -323,6 +342,10 @@ func resourceObjectImport(d *schema.ResourceData, meta interface{}) ([]*schema.R
 	bucket := parts[0]
 	key := strings.Join(parts[1:], "/")
 
+	if err := CheckAllowedBucket(meta.(Client), bucket); err != nil {
+		return nil, err
+	}
+
 	d.SetId(key)
 	d.Set("bucket", bucket)
 	d.Set("key", key)
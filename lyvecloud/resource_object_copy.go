@@ -12,6 +12,7 @@ import (
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/s3"
 	"github.com/hashicorp/aws-sdk-go-base/tfawserr"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
@@ -23,6 +24,11 @@ func ResourceObjectCopy() *schema.Resource {
 		Update: resourceObjectCopyUpdate,
 		Delete: resourceObjectCopyDelete,
 
+		CustomizeDiff: customdiff.Sequence(
+			customizeDiffAllowedBucket,
+			customizeDiffAllowedCopySource,
+		),
+
 		Schema: map[string]*schema.Schema{
 			"bucket": {
 				Type:         schema.TypeString,
@@ -145,6 +151,10 @@ func ResourceObjectCopy() *schema.Resource {
 }
 
 func resourceObjectCopyCreate(d *schema.ResourceData, meta interface{}) error {
+	if err := CheckReadOnly(meta.(Client), "create object copy"); err != nil {
+		return err
+	}
+
 	return resourceObjectCopyDoCopy(d, meta)
 }
 
@@ -224,6 +234,10 @@ func resourceObjectCopyRead(d *schema.ResourceData, meta interface{}) error {
 }
 
 func resourceObjectCopyUpdate(d *schema.ResourceData, meta interface{}) error {
+	if err := CheckReadOnly(meta.(Client), "update object copy"); err != nil {
+		return err
+	}
+
 	// if any of these exist, let the API decide whether to copy
 	for _, key := range []string{
 		"copy_if_match",
@@ -264,10 +278,20 @@ func resourceObjectCopyDelete(d *schema.ResourceData, meta interface{}) error {
 		return fmt.Errorf("credentials for S3 operations are missing")
 	}
 
+	if err := CheckReadOnly(meta.(Client), "delete object copy"); err != nil {
+		return err
+	}
+
 	conn := *meta.(Client).S3Client
 
 	bucket := d.Get("bucket").(string)
 	key := d.Get("key").(string)
+
+	// CustomizeDiff doesn't run on destroy.
+	if err := CheckAllowedBucket(meta.(Client), bucket); err != nil {
+		return err
+	}
+
 	// We are effectively ignoring all leading '/'s in the key name and
 	// treating multiple '/'s as a single '/' as aws.Config.DisableRestProtocolURICleaning is false
 	key = strings.TrimLeft(key, "/")
@@ -286,6 +310,11 @@ func resourceObjectCopyDoCopy(d *schema.ResourceData, meta interface{}) error {
 		return fmt.Errorf("credentials for S3 operations are missing")
 	}
 
+	// the source bucket may only be known now.
+	if err := CheckAllowedBucket(meta.(Client), copySourceBucket(d.Get("source").(string))); err != nil {
+		return err
+	}
+
 	conn := *meta.(Client).S3Client
 	tags := New(d.Get("tags").(map[string]interface{}))
 
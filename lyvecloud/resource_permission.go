@@ -10,6 +10,8 @@ import (
 	"time"
 
 	awspolicy "github.com/hashicorp/awspolicyequivalence"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
@@ -20,10 +22,26 @@ type EscapeError string
 func ResourcePermission() *schema.Resource {
 
 	return &schema.Resource{
-		Create: resourcePermissionCreate,
-		Read:   resourcePermissionRead,
-		Update: resourcePermissionUpdate,
-		Delete: resourcePermissionDelete,
+		Create:      resourcePermissionCreate,
+		ReadContext: resourcePermissionReadWithDiags,
+		Update:      resourcePermissionUpdate,
+		Delete:      resourcePermissionDelete,
+
+		SchemaVersion: 1,
+		StateUpgraders: []schema.StateUpgrader{
+			{
+				Type:    resourcePermissionV0().CoreConfigSchema().ImpliedType(),
+				Upgrade: resourcePermissionStateUpgradeV0,
+				Version: 0,
+			},
+		},
+
+		CustomizeDiff: customdiff.Sequence(
+			resourcePermissionPolicyCustomizeDiff,
+			resourcePermissionTypeCustomizeDiff,
+			resourcePermissionBucketsCustomizeDiff,
+			resourcePermissionAdoptCustomizeDiff,
+		),
 
 		Schema: map[string]*schema.Schema{
 			"name": {
@@ -69,23 +87,51 @@ func ResourcePermission() *schema.Resource {
 				RequiredWith:  []string{"actions"},
 			},
 			"buckets": {
-				Type:     schema.TypeList,
+				Type:     schema.TypeSet,
 				Optional: true,
 				Elem: &schema.Schema{
 					Type: schema.TypeString,
 				},
+				Set:           schema.HashString,
 				ConflictsWith: []string{"bucket_prefix", "all_buckets", "policy"},
 				RequiredWith:  []string{"actions"},
 			},
 			"policy": {
-				Type:          schema.TypeString,
-				Optional:      true,
-				ConflictsWith: []string{"bucket_prefix", "all_buckets", "buckets", "actions"},
+				Type:             schema.TypeString,
+				Optional:         true,
+				ConflictsWith:    []string{"bucket_prefix", "all_buckets", "buckets", "actions"},
+				ValidateDiagFunc: validatePermissionPolicyDiag,
+				DiffSuppressFunc: suppressEquivalentPolicyDiffs,
 				StateFunc: func(v interface{}) string {
 					json, _ := NormalizeJsonString(v)
 					return json
 				},
 			},
+			"validate_buckets": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"warn_on_bucket_drift": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"replace_on_type_change": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  true,
+			},
+			"adopt_existing": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"detach_on_destroy": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
 			"ready_state": {
 				Type:     schema.TypeBool,
 				Computed: true,
@@ -103,6 +149,10 @@ func resourcePermissionCreate(d *schema.ResourceData, meta interface{}) error {
 		return fmt.Errorf("credentials for account api are missing")
 	}
 
+	if err := CheckReadOnly(meta.(Client), "create permission"); err != nil {
+		return err
+	}
+
 	conn := *meta.(Client).AccountAPIClient
 
 	name := NameWithSuffix(d.Get("name").(string), d.Get("name_prefix").(string))
@@ -122,7 +172,7 @@ func resourcePermissionCreate(d *schema.ResourceData, meta interface{}) error {
 	} else if v, ok := d.GetOk("buckets"); ok {
 		permissionType = "bucket-names"
 		var err error
-		buckets, err = convertBucketsList(v.([]interface{}))
+		buckets, err = convertBucketsList(v.(*schema.Set).List())
 		if err != nil {
 			return fmt.Errorf("error reading buckets list: %w", err)
 		}
@@ -149,6 +199,10 @@ func resourcePermissionCreate(d *schema.ResourceData, meta interface{}) error {
 	}
 
 	resp, err := conn.CreatePermission(&createPermissionInput)
+	if err != nil && d.Get("adopt_existing").(bool) {
+		log.Printf("[WARN] Error creating permission (%s), looking for an existing permission to adopt: %s", name, err)
+		resp, err = adoptExistingPermission(&conn, &createPermissionInput, err)
+	}
 	if err != nil {
 		return fmt.Errorf("error creating permission: %w", err)
 	}
@@ -219,15 +273,31 @@ func resourcePermissionRead(d *schema.ResourceData, meta interface{}) error {
 	return nil
 }
 
+// resourcePermissionReadWithDiags reads the permission and warns about referenced buckets that no longer exist.
+func resourcePermissionReadWithDiags(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	if err := resourcePermissionRead(d, meta); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return permissionBucketsDriftDiags(ctx, d, meta)
+}
+
 func resourcePermissionUpdate(d *schema.ResourceData, meta interface{}) error {
 	if CheckCredentials(AccountAPI, meta.(Client)) {
 		return fmt.Errorf("credentials for account api are missing")
 	}
 
+	if err := CheckReadOnly(meta.(Client), "update permission"); err != nil {
+		return err
+	}
+
 	conn := *meta.(Client).AccountAPIClient
 
 	permissionId := d.Id()
 
+	accountAPIMutexKV.Lock(permissionMutexKey(permissionId))
+	defer accountAPIMutexKV.Unlock(permissionMutexKey(permissionId))
+
 	name := NameWithSuffix(d.Get("name").(string), d.Get("name_prefix").(string))
 	description := d.Get("description").(string)
 	actions := d.Get("actions").(string)
@@ -245,7 +315,7 @@ func resourcePermissionUpdate(d *schema.ResourceData, meta interface{}) error {
 	} else if v, ok := d.GetOk("buckets"); ok {
 		permissionType = "bucket-names"
 		var err error
-		buckets, err = convertBucketsList(v.([]interface{}))
+		buckets, err = convertBucketsList(v.(*schema.Set).List())
 		if err != nil {
 			return fmt.Errorf("error reading buckets list: %w", err)
 		}
@@ -273,7 +343,7 @@ func resourcePermissionUpdate(d *schema.ResourceData, meta interface{}) error {
 
 	_, err := conn.UpdatePermission(permissionId, &updatePermissinInput)
 
-	if !d.IsNewResource() && err.Error() == PermissionNotFound {
+	if !d.IsNewResource() && err != nil && err.Error() == PermissionNotFound {
 		log.Printf("[WARN] Permission (%s) not found, removing from state", d.Id())
 		d.SetId("")
 		return nil
@@ -291,9 +361,37 @@ func resourcePermissionDelete(d *schema.ResourceData, meta interface{}) error {
 		return fmt.Errorf("credentials for account api are missing")
 	}
 
+	if err := CheckReadOnly(meta.(Client), "delete permission"); err != nil {
+		return err
+	}
+
 	conn := *meta.(Client).AccountAPIClient
 
-	_, err := conn.DeletePermission(d.Id())
+	permissionId := d.Id()
+
+	// service accounts outside of the configuration may reference the permission, deleting it would leave them broken.
+	serviceAccounts, err := findServiceAccountsByPermission(&conn, permissionId)
+	if err != nil {
+		return fmt.Errorf("error deleting permission (%s): %w", permissionId, err)
+	}
+
+	if len(serviceAccounts) > 0 && !d.Get("detach_on_destroy").(bool) {
+		attached := make([]string, 0, len(serviceAccounts))
+		for _, serviceAccount := range serviceAccounts {
+			attached = append(attached, fmt.Sprintf("%s (%s)", serviceAccount.Name, serviceAccount.Id))
+		}
+		return fmt.Errorf("error deleting permission (%s): still attached to service accounts %s; detach it from them or set detach_on_destroy", permissionId, strings.Join(attached, ", "))
+	}
+
+	for _, serviceAccount := range serviceAccounts {
+		log.Printf("[DEBUG] Detaching permission (%s) from service account (%s)", permissionId, serviceAccount.Id)
+
+		if err := detachServiceAccountPermission(&conn, serviceAccount.Id, permissionId); err != nil {
+			return fmt.Errorf("error deleting permission (%s): %w", permissionId, err)
+		}
+	}
+
+	_, err = conn.DeletePermission(permissionId)
 	if err != nil {
 		return fmt.Errorf("error deleting permission: %w", err)
 	}
@@ -301,6 +399,193 @@ func resourcePermissionDelete(d *schema.ResourceData, meta interface{}) error {
 	return nil
 }
 
+// findServiceAccountsByPermission returns the service accounts the permission is attached to.
+func findServiceAccountsByPermission(conn *AuthData, permissionId string) ([]GetServiceAccountResponse, error) {
+	summaries, err := conn.ListServiceAccounts()
+	if err != nil {
+		return nil, fmt.Errorf("error listing service accounts: %w", err)
+	}
+
+	var serviceAccounts []GetServiceAccountResponse
+	for _, summary := range summaries {
+		// the list response does not carry the permissions of each service account.
+		serviceAccount, err := conn.GetServiceAccount(summary.Id)
+		if err != nil && err.Error() == ServiceAccountNotFound {
+			continue
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("error reading service account (%s): %w", summary.Id, err)
+		}
+
+		if stringInSlice(permissionId, serviceAccount.Permissions) {
+			serviceAccounts = append(serviceAccounts, *serviceAccount)
+		}
+	}
+
+	return serviceAccounts, nil
+}
+
+// resourcePermissionTypeCustomizeDiff shows the permission type in the plan and, unless
+// replace_on_type_change is false, replaces the permission when its type changes, as some
+// Account API deployments reject an update to another type, failing the apply half-done.
+func resourcePermissionTypeCustomizeDiff(_ context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	for _, key := range []string{"all_buckets", "bucket_prefix", "buckets", "policy"} {
+		if !d.NewValueKnown(key) {
+			return nil
+		}
+	}
+
+	var permissionType string
+	if d.Get("all_buckets").(bool) {
+		permissionType = "all-buckets"
+	} else if d.Get("bucket_prefix").(string) != "" {
+		permissionType = "bucket-prefix"
+	} else if _, ok := d.GetOk("buckets"); ok {
+		permissionType = "bucket-names"
+	} else if _, ok := d.GetOk("policy"); ok {
+		permissionType = "policy"
+	} else {
+		return nil
+	}
+
+	oldType := d.Get("type").(string)
+	if oldType == permissionType {
+		return nil
+	}
+
+	if err := d.SetNew("type", permissionType); err != nil {
+		return err
+	}
+
+	if d.Id() == "" || oldType == "" || !d.Get("replace_on_type_change").(bool) {
+		return nil
+	}
+
+	log.Printf("[DEBUG] Permission (%s) type changes from %s to %s, replacing", d.Id(), oldType, permissionType)
+
+	return d.ForceNew("type")
+}
+
+// findPermissionByName returns the permission with the given name, failing if several permissions have it.
+func findPermissionByName(conn *AuthData, name string) (*GetPermissionResponse, error) {
+	permissions, err := conn.ListPermissions()
+	if err != nil {
+		return nil, fmt.Errorf("error listing permissions: %w", err)
+	}
+
+	var found *GetPermissionResponse
+	var ids []string
+	for i := range permissions {
+		if permissions[i].Name == name {
+			found = &permissions[i]
+			ids = append(ids, permissions[i].Id)
+		}
+	}
+
+	if len(ids) > 1 {
+		return nil, fmt.Errorf("multiple permissions named %s: %s", name, strings.Join(ids, ", "))
+	}
+
+	if found == nil {
+		return nil, fmt.Errorf("permission (%s) not found", name)
+	}
+
+	return found, nil
+}
+
+// resourcePermissionAdoptCustomizeDiff rejects adopt_existing on a new permission without a name, as the name
+// generated from name_prefix, or from scratch, can't match an existing permission.
+func resourcePermissionAdoptCustomizeDiff(_ context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if d.Id() != "" || !d.Get("adopt_existing").(bool) {
+		return nil
+	}
+
+	generated := d.Get("name_prefix").(string) != ""
+	if rawConfig := d.GetRawConfig(); !rawConfig.IsNull() && rawConfig.GetAttr("name").IsNull() {
+		generated = true
+	}
+
+	if generated {
+		return fmt.Errorf("adopt_existing requires name, a generated name can't match an existing permission")
+	}
+
+	return nil
+}
+
+// adoptExistingPermission returns the ID of the permission named as the input, if it matches the input,
+// so that a create that failed or timed out after the permission was committed can complete.
+// createErr is returned if there is no such permission.
+func adoptExistingPermission(conn *AuthData, input *Permission, createErr error) (*PermissionResponse, error) {
+	permission, err := findPermissionByName(conn, input.Name)
+	if err != nil {
+		log.Printf("[DEBUG] No permission to adopt: %s", err)
+		return nil, createErr
+	}
+
+	existing, err := conn.GetPermission(permission.Id)
+	if err != nil {
+		return nil, fmt.Errorf("%w; error reading permission (%s) to adopt: %s", createErr, permission.Id, err)
+	}
+
+	differences, err := permissionDifferences(existing, input)
+	if err != nil {
+		return nil, fmt.Errorf("%w; error comparing permission (%s) to adopt: %s", createErr, permission.Id, err)
+	}
+
+	if len(differences) > 0 {
+		return nil, fmt.Errorf("%w; permission (%s) named %s exists but can't be adopted, its %s differ", createErr, permission.Id, input.Name, strings.Join(differences, ", "))
+	}
+
+	log.Printf("[INFO] Adopting existing permission (%s) named %s", permission.Id, input.Name)
+
+	return &PermissionResponse{ID: permission.Id}, nil
+}
+
+// permissionDifferences returns the names of the fields in which the existing permission differs from the input.
+func permissionDifferences(existing *GetPermissionResponse, input *Permission) ([]string, error) {
+	var differences []string
+
+	if existing.Description != input.Description {
+		differences = append(differences, "description")
+	}
+
+	if existing.Type != input.Type {
+		return append(differences, "type"), nil
+	}
+
+	if input.Type != "policy" && existing.Actions != input.Actions {
+		differences = append(differences, "actions")
+	}
+
+	switch input.Type {
+	case "bucket-prefix":
+		if existing.Prefix != input.Prefix {
+			differences = append(differences, "bucket_prefix")
+		}
+	case "bucket-names":
+		if !stringSetsEqual(existing.Buckets, input.Buckets) {
+			differences = append(differences, "buckets")
+		}
+	case "policy":
+		policy, err := unescape(existing.Policy)
+		if err != nil {
+			return nil, fmt.Errorf("Error parsing policy: %s", err)
+		}
+
+		equivalent, err := awspolicy.PoliciesAreEquivalent(policy, input.Policy)
+		if err != nil {
+			return nil, err
+		}
+
+		if !equivalent {
+			differences = append(differences, "policy")
+		}
+	}
+
+	return differences, nil
+}
+
 // Takes a value containing JSON string and passes it through
 // the JSON parser to normalize it, returns either a parsing
 // error or normalized JSON string.
@@ -414,6 +699,21 @@ func unhex(c byte) byte {
 	return 0
 }
 
+// suppressEquivalentPolicyDiffs suppresses the diff between equivalent policies,
+// using the same equivalence as SecondJSONUnlessEquivalent.
+func suppressEquivalentPolicyDiffs(k, old, new string, d *schema.ResourceData) bool {
+	if strings.TrimSpace(old) == "" || strings.TrimSpace(new) == "" {
+		return strings.TrimSpace(old) == strings.TrimSpace(new)
+	}
+
+	equivalent, err := awspolicy.PoliciesAreEquivalent(old, new)
+	if err != nil {
+		return false
+	}
+
+	return equivalent
+}
+
 func SecondJSONUnlessEquivalent(old, new string) (string, error) {
 	// valid empty JSON is "{}" not "" so handle special case to avoid
 	// Error unmarshaling policy: unexpected end of JSON input
@@ -455,6 +755,31 @@ func NameWithSuffix(name string, namePrefix string) string {
 	return resource.UniqueId()
 }
 
+// stringSetsEqual returns true if a and b hold the same strings, ignoring order and duplicates.
+func stringSetsEqual(a, b []string) bool {
+	setA := make(map[string]bool, len(a))
+	for _, v := range a {
+		setA[v] = true
+	}
+
+	setB := make(map[string]bool, len(b))
+	for _, v := range b {
+		setB[v] = true
+	}
+
+	if len(setA) != len(setB) {
+		return false
+	}
+
+	for v := range setA {
+		if !setB[v] {
+			return false
+		}
+	}
+
+	return true
+}
+
 func convertBucketsList(bucketsList []interface{}) ([]string, error) {
 	buckets := []string{}
 	for _, v := range bucketsList {
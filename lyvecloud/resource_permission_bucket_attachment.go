@@ -0,0 +1,177 @@
+package lyvecloud
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func ResourcePermissionBucketAttachment() *schema.Resource {
+
+	return &schema.Resource{
+		Create: resourcePermissionBucketAttachmentCreate,
+		Read:   resourcePermissionBucketAttachmentRead,
+		Delete: resourcePermissionBucketAttachmentDelete,
+
+		Importer: &schema.ResourceImporter{
+			StateContext: resourcePermissionBucketAttachmentImport,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"permission_id": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"bucket": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+		},
+	}
+}
+
+func resourcePermissionBucketAttachmentCreate(d *schema.ResourceData, meta interface{}) error {
+	if CheckCredentials(AccountAPI, meta.(Client)) {
+		return fmt.Errorf("credentials for account api are missing")
+	}
+
+	if err := CheckReadOnly(meta.(Client), "attach bucket to permission"); err != nil {
+		return err
+	}
+
+	conn := *meta.(Client).AccountAPIClient
+
+	permissionId := d.Get("permission_id").(string)
+	bucket := d.Get("bucket").(string)
+
+	accountAPIMutexKV.Lock(permissionMutexKey(permissionId))
+	defer accountAPIMutexKV.Unlock(permissionMutexKey(permissionId))
+
+	permission, err := conn.GetPermission(permissionId)
+	if err != nil {
+		return fmt.Errorf("error reading permission (%s): %w", permissionId, err)
+	}
+
+	if permission.Type != "bucket-names" {
+		return fmt.Errorf("error attaching bucket (%s) to permission (%s): buckets can only be attached to bucket-names permissions, not %s", bucket, permissionId, permission.Type)
+	}
+
+	if !stringInSlice(bucket, permission.Buckets) {
+		_, err = conn.UpdatePermission(permissionId, permissionBucketsInput(permission, append(permission.Buckets, bucket)))
+		if err != nil {
+			return fmt.Errorf("error attaching bucket (%s) to permission (%s): %w", bucket, permissionId, err)
+		}
+	}
+
+	d.SetId(permissionId + SlashSeparator + bucket)
+
+	return resourcePermissionBucketAttachmentRead(d, meta)
+}
+
+func resourcePermissionBucketAttachmentRead(d *schema.ResourceData, meta interface{}) error {
+	if CheckCredentials(AccountAPI, meta.(Client)) {
+		return fmt.Errorf("credentials for account api are missing")
+	}
+
+	conn := *meta.(Client).AccountAPIClient
+
+	permissionId := d.Get("permission_id").(string)
+	bucket := d.Get("bucket").(string)
+
+	permission, err := conn.GetPermission(permissionId)
+
+	if !d.IsNewResource() && err != nil && err.Error() == PermissionNotFound {
+		log.Printf("[WARN] Permission (%s) not found, removing attachment (%s) from state", permissionId, d.Id())
+		d.SetId("")
+		return nil
+	}
+
+	if err != nil {
+		return fmt.Errorf("error reading permission (%s): %w", permissionId, err)
+	}
+
+	if !d.IsNewResource() && !stringInSlice(bucket, permission.Buckets) {
+		log.Printf("[WARN] Bucket (%s) not attached to permission (%s), removing attachment (%s) from state", bucket, permissionId, d.Id())
+		d.SetId("")
+		return nil
+	}
+
+	return nil
+}
+
+func resourcePermissionBucketAttachmentDelete(d *schema.ResourceData, meta interface{}) error {
+	if CheckCredentials(AccountAPI, meta.(Client)) {
+		return fmt.Errorf("credentials for account api are missing")
+	}
+
+	if err := CheckReadOnly(meta.(Client), "detach bucket from permission"); err != nil {
+		return err
+	}
+
+	conn := *meta.(Client).AccountAPIClient
+
+	permissionId := d.Get("permission_id").(string)
+	bucket := d.Get("bucket").(string)
+
+	accountAPIMutexKV.Lock(permissionMutexKey(permissionId))
+	defer accountAPIMutexKV.Unlock(permissionMutexKey(permissionId))
+
+	permission, err := conn.GetPermission(permissionId)
+	if err != nil && err.Error() == PermissionNotFound {
+		return nil
+	}
+
+	if err != nil {
+		return fmt.Errorf("error reading permission (%s): %w", permissionId, err)
+	}
+
+	if !stringInSlice(bucket, permission.Buckets) {
+		return nil
+	}
+
+	buckets := []string{}
+	for _, v := range permission.Buckets {
+		if v != bucket {
+			buckets = append(buckets, v)
+		}
+	}
+
+	// an empty bucket list is not a valid bucket-names permission, it is not sent to the Account API.
+	if len(buckets) == 0 {
+		return fmt.Errorf("error detaching bucket (%s) from permission (%s): it is the last bucket of the permission, which needs at least one; keep another bucket in the permission or delete the permission", bucket, permissionId)
+	}
+
+	_, err = conn.UpdatePermission(permissionId, permissionBucketsInput(permission, buckets))
+	if err != nil && err.Error() != PermissionNotFound {
+		return fmt.Errorf("error detaching bucket (%s) from permission (%s): %w", bucket, permissionId, err)
+	}
+
+	return nil
+}
+
+func resourcePermissionBucketAttachmentImport(_ context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	permissionId, bucket, err := parseAttachmentId(d.Id(), "permission-id/bucket")
+	if err != nil {
+		return nil, err
+	}
+
+	d.Set("permission_id", permissionId)
+	d.Set("bucket", bucket)
+
+	return []*schema.ResourceData{d}, nil
+}
+
+// permissionBucketsInput returns the UpdatePermission input that sets the buckets of a bucket-names permission.
+func permissionBucketsInput(permission *GetPermissionResponse, buckets []string) *Permission {
+	return &Permission{
+		Name:        permission.Name,
+		Description: permission.Description,
+		Type:        permission.Type,
+		Actions:     permission.Actions,
+		Buckets:     buckets,
+	}
+}
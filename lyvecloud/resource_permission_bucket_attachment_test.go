@@ -0,0 +1,84 @@
+package lyvecloud
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestResourcePermissionBucketAttachmentDelete(t *testing.T) {
+	testCases := []struct {
+		Name          string
+		Buckets       []string
+		ExpectedError string
+		Expected      []string
+	}{
+		{
+			Name:     "other buckets",
+			Buckets:  []string{"shared-bucket", "team-bucket"},
+			Expected: []string{"shared-bucket"},
+		},
+		{
+			Name:          "last bucket",
+			Buckets:       []string{"team-bucket"},
+			ExpectedError: "it is the last bucket of the permission",
+		},
+		{
+			Name:    "not attached",
+			Buckets: []string{"shared-bucket"},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			var updated []string
+			api := newFakeAccountAPI(t, map[string]http.HandlerFunc{
+				"GET /v2/permissions/permission-id": jsonHandler(http.StatusOK, GetPermissionResponse{
+					Id:      "permission-id",
+					Name:    "permission",
+					Type:    "bucket-names",
+					Actions: "read-only",
+					Buckets: testCase.Buckets,
+				}),
+				"PUT /v2/permissions/permission-id": func(w http.ResponseWriter, r *http.Request) {
+					var permission Permission
+					if err := json.NewDecoder(r.Body).Decode(&permission); err != nil {
+						t.Errorf("error decoding permission: %s", err)
+					}
+					updated = permission.Buckets
+					jsonHandler(http.StatusOK, nil)(w, r)
+				},
+			})
+
+			d := schema.TestResourceDataRaw(t, ResourcePermissionBucketAttachment().Schema, map[string]interface{}{
+				"permission_id": "permission-id",
+				"bucket":        "team-bucket",
+			})
+			d.SetId("permission-id/team-bucket")
+
+			err := resourcePermissionBucketAttachmentDelete(d, Client{AccountAPIClient: &AuthData{}})
+			if testCase.ExpectedError == "" && err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if testCase.ExpectedError != "" && (err == nil || !strings.Contains(err.Error(), testCase.ExpectedError)) {
+				t.Fatalf("got error %v, expected it to contain %q", err, testCase.ExpectedError)
+			}
+
+			expectedUpdates := 0
+			if testCase.Expected != nil {
+				expectedUpdates = 1
+			}
+			if got := api.count("PUT /v2/permissions/permission-id"); got != expectedUpdates {
+				t.Fatalf("got %d permission updates, expected %d", got, expectedUpdates)
+			}
+
+			if !reflect.DeepEqual(updated, testCase.Expected) {
+				t.Errorf("got buckets %v, expected %v", updated, testCase.Expected)
+			}
+		})
+	}
+}
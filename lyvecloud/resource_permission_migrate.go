@@ -0,0 +1,138 @@
+package lyvecloud
+
+import (
+	"context"
+	"log"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// resourcePermissionV0 is the schema of lyvecloud_permission released before the schema versioning,
+// where buckets is a list.
+func resourcePermissionV0() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				Computed:      true,
+				ConflictsWith: []string{"name_prefix"},
+			},
+			"name_prefix": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				Computed:      true,
+				ConflictsWith: []string{"name"},
+			},
+			"description": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"type": {
+				Type:     schema.TypeString,
+				Computed: true, // computed based on the chosen argument. all_buckets/prefix/buckets/policy
+			},
+			"actions": {
+				Type:     schema.TypeString,
+				Optional: true,
+				ValidateFunc: validation.StringInSlice([]string{
+					"all-operations",
+					"read-only",
+					"write-only",
+				}, false),
+				ConflictsWith: []string{"policy"},
+			},
+			"all_buckets": {
+				Type:          schema.TypeBool,
+				Optional:      true,
+				ConflictsWith: []string{"buckets", "bucket_prefix", "policy"},
+				RequiredWith:  []string{"actions"},
+			},
+			"bucket_prefix": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{"buckets", "all_buckets", "policy"},
+				RequiredWith:  []string{"actions"},
+			},
+			"buckets": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				ConflictsWith: []string{"bucket_prefix", "all_buckets", "policy"},
+				RequiredWith:  []string{"actions"},
+			},
+			"policy": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{"bucket_prefix", "all_buckets", "buckets", "actions"},
+				StateFunc: func(v interface{}) string {
+					json, _ := NormalizeJsonString(v)
+					return json
+				},
+			},
+			"ready_state": {
+				Type:     schema.TypeBool,
+				Computed: true,
+			},
+			"id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+// resourcePermissionStateUpgradeV0 migrates buckets from a list to a set, and sets the arguments added since
+// version 0 to their defaults, which would otherwise be planned as changes.
+func resourcePermissionStateUpgradeV0(_ context.Context, rawState map[string]interface{}, meta interface{}) (map[string]interface{}, error) {
+	if rawState == nil {
+		return nil, nil
+	}
+
+	log.Printf("[DEBUG] Upgrading permission state from version 0")
+
+	upgradeStateListToSet(rawState, "buckets")
+
+	upgradeStateDefaults(rawState, map[string]interface{}{
+		"validate_buckets":       false,
+		"warn_on_bucket_drift":   false,
+		"replace_on_type_change": true,
+		"adopt_existing":         false,
+		"detach_on_destroy":      false,
+	})
+
+	return rawState, nil
+}
+
+// upgradeStateDefaults sets the attributes missing from the raw state to their default.
+func upgradeStateDefaults(rawState map[string]interface{}, defaults map[string]interface{}) {
+	for key, v := range defaults {
+		if rawState[key] == nil {
+			rawState[key] = v
+		}
+	}
+}
+
+// upgradeStateListToSet drops the duplicate elements of a list that becomes a set, keeping the first of each.
+// Lists and sets share the same raw state representation, so the elements need no other change.
+func upgradeStateListToSet(rawState map[string]interface{}, key string) {
+	list, ok := rawState[key].([]interface{})
+	if !ok {
+		return
+	}
+
+	set := make([]interface{}, 0, len(list))
+	seen := make(map[interface{}]bool, len(list))
+	for _, v := range list {
+		if seen[v] {
+			continue
+		}
+		seen[v] = true
+		set = append(set, v)
+	}
+
+	rawState[key] = set
+}
@@ -0,0 +1,496 @@
+package lyvecloud
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/go-cty/cty"
+	ctyjson "github.com/hashicorp/go-cty/cty/json"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestResourcePermissionCustomizeDiff_type(t *testing.T) {
+	state := &terraform.InstanceState{
+		ID: "permission-id",
+		Attributes: map[string]string{
+			"id":          "permission-id",
+			"name":        "terraform-20230101000000000000000001",
+			"description": "description",
+			"type":        "bucket-names",
+			"actions":     "read-only",
+			"buckets.#":   "1",
+			"buckets." + strconv.Itoa(schema.HashString("my-bucket")): "my-bucket",
+			"replace_on_type_change":                                  "true",
+		},
+	}
+
+	prefixedState := state.DeepCopy()
+	prefixedState.Attributes["name"] = "team-20230101000000000000000001"
+	prefixedState.Attributes["name_prefix"] = "team-"
+
+	testCases := []struct {
+		Name        string
+		State       *terraform.InstanceState
+		Raw         map[string]interface{}
+		Type        string
+		RequiresNew bool
+	}{
+		{
+			Name: "same type",
+			Raw: map[string]interface{}{
+				"description": "description",
+				"actions":     "read-only",
+				"buckets":     []interface{}{"my-bucket", "other-bucket"},
+			},
+			Type: "bucket-names",
+		},
+		{
+			Name: "to policy in place",
+			Raw: map[string]interface{}{
+				"description":            "description",
+				"policy":                 `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":"s3:GetObject","Resource":"arn:aws:s3:::my-bucket/*"}]}`,
+				"replace_on_type_change": false,
+			},
+			Type: "policy",
+		},
+		{
+			Name: "to policy replacing by default",
+			Raw: map[string]interface{}{
+				"description": "description",
+				"policy":      `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":"s3:GetObject","Resource":"arn:aws:s3:::my-bucket/*"}]}`,
+			},
+			Type:        "policy",
+			RequiresNew: true,
+		},
+		{
+			// the replacement gets a new name, so it can be created before the permission it replaces is destroyed.
+			Name:  "to policy replacing with name prefix",
+			State: prefixedState,
+			Raw: map[string]interface{}{
+				"name_prefix": "team-",
+				"description": "description",
+				"policy":      `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":"s3:GetObject","Resource":"arn:aws:s3:::my-bucket/*"}]}`,
+			},
+			Type:        "policy",
+			RequiresNew: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			state := state
+			if testCase.State != nil {
+				state = testCase.State
+			}
+
+			diff, err := ResourcePermission().Diff(context.Background(), state.DeepCopy(), terraform.NewResourceConfigRaw(testCase.Raw), nil)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if got := diff != nil && diff.RequiresNew(); got != testCase.RequiresNew {
+				t.Errorf("got RequiresNew %t, expected %t", got, testCase.RequiresNew)
+			}
+
+			got := state.Attributes["type"]
+			if diff != nil && diff.Attributes["type"] != nil {
+				got = diff.Attributes["type"].New
+			}
+			if got != testCase.Type {
+				t.Errorf("got type %q, expected %q", got, testCase.Type)
+			}
+
+			if testCase.RequiresNew && !diff.Attributes["name"].NewComputed {
+				t.Errorf("expected a new name to be generated")
+			}
+
+			if attr := diff.Attributes["name_prefix"]; attr != nil && attr.New != state.Attributes["name_prefix"] {
+				t.Errorf("got name_prefix %q, expected %q", attr.New, state.Attributes["name_prefix"])
+			}
+		})
+	}
+}
+
+func TestResourcePermissionCustomizeDiff_policy(t *testing.T) {
+	policy := `{"Statement":[{"Action":"s3:GetObject","Effect":"Allow","Resource":"arn:aws:s3:::My_Bucket/*"}],"Version":"2012-10-17"}`
+	state := &terraform.InstanceState{
+		ID: "permission-id",
+		Attributes: map[string]string{
+			"id":          "permission-id",
+			"name":        "terraform-20230101000000000000000001",
+			"description": "description",
+			"type":        "policy",
+			"policy":      policy,
+		},
+	}
+
+	testCases := []struct {
+		Name     string
+		Policy   string
+		Expected string
+	}{
+		{
+			Name:   "unchanged",
+			Policy: policy,
+		},
+		{
+			Name:     "changed",
+			Policy:   `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":"s3:PutObject","Resource":"arn:aws:s3:::My_Bucket/*"}]}`,
+			Expected: `Resource "arn:aws:s3:::My_Bucket/*" is not a valid bucket or object ARN`,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			raw := map[string]interface{}{
+				"description": "description",
+				"policy":      testCase.Policy,
+			}
+
+			_, err := ResourcePermission().Diff(context.Background(), state.DeepCopy(), terraform.NewResourceConfigRaw(raw), nil)
+			if testCase.Expected == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %s", err)
+				}
+				return
+			}
+
+			if err == nil || !strings.Contains(err.Error(), testCase.Expected) {
+				t.Fatalf("got error %v, expected it to contain %q", err, testCase.Expected)
+			}
+		})
+	}
+}
+
+func TestResourcePermissionCustomizeDiff_adopt(t *testing.T) {
+	testCases := []struct {
+		Name     string
+		Raw      map[string]interface{}
+		Expected string
+	}{
+		{
+			Name: "name",
+			Raw:  map[string]interface{}{"name": "existing"},
+		},
+		{
+			Name:     "name prefix",
+			Raw:      map[string]interface{}{"name_prefix": "existing-"},
+			Expected: "adopt_existing requires name",
+		},
+		{
+			Name:     "generated name",
+			Raw:      map[string]interface{}{},
+			Expected: "adopt_existing requires name",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			raw := map[string]interface{}{
+				"description":    "description",
+				"actions":        "read-only",
+				"all_buckets":    true,
+				"adopt_existing": true,
+			}
+			for k, v := range testCase.Raw {
+				raw[k] = v
+			}
+
+			// the raw config tells a name left to be generated from an unknown one.
+			rawJSON, err := json.Marshal(raw)
+			if err != nil {
+				t.Fatal(err)
+			}
+			rawConfig, err := ctyjson.Unmarshal(rawJSON, ResourcePermission().CoreConfigSchema().ImpliedType())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			_, err = ResourcePermission().Diff(context.Background(), &terraform.InstanceState{RawConfig: rawConfig}, terraform.NewResourceConfigRaw(raw), nil)
+			if testCase.Expected == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %s", err)
+				}
+				return
+			}
+
+			if err == nil || !strings.Contains(err.Error(), testCase.Expected) {
+				t.Fatalf("got error %v, expected it to contain %q", err, testCase.Expected)
+			}
+		})
+	}
+}
+
+func TestResourcePermissionDelete(t *testing.T) {
+	testCases := []struct {
+		Name            string
+		DetachOnDestroy bool
+		Attached        bool
+		ExpectedError   string
+		ExpectedCalls   map[string]int
+	}{
+		{
+			Name: "detached",
+			ExpectedCalls: map[string]int{
+				"GET /v2/service-accounts":             1,
+				"PUT /v2/service-accounts/sa-1":        0,
+				"DELETE /v2/permissions/permission-id": 1,
+			},
+		},
+		{
+			Name:          "attached",
+			Attached:      true,
+			ExpectedError: "still attached to service accounts sa-1 (sa-1)",
+			ExpectedCalls: map[string]int{
+				"GET /v2/service-accounts":             1,
+				"PUT /v2/service-accounts/sa-1":        0,
+				"DELETE /v2/permissions/permission-id": 0,
+			},
+		},
+		{
+			Name:            "detached with detach_on_destroy",
+			DetachOnDestroy: true,
+			ExpectedCalls: map[string]int{
+				"GET /v2/service-accounts":             1,
+				"PUT /v2/service-accounts/sa-1":        0,
+				"DELETE /v2/permissions/permission-id": 1,
+			},
+		},
+		{
+			Name:            "attached with detach_on_destroy",
+			DetachOnDestroy: true,
+			Attached:        true,
+			ExpectedCalls: map[string]int{
+				"GET /v2/service-accounts":             1,
+				"GET /v2/service-accounts/sa-1":        2,
+				"GET /v2/service-accounts/sa-2":        1,
+				"PUT /v2/service-accounts/sa-1":        1,
+				"PUT /v2/service-accounts/sa-2":        0,
+				"DELETE /v2/permissions/permission-id": 1,
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			attached := testCase.Attached
+			api := newFakeAccountAPI(t, map[string]http.HandlerFunc{
+				"DELETE /v2/permissions/permission-id": func(w http.ResponseWriter, r *http.Request) {
+					if attached {
+						jsonHandler(http.StatusConflict, map[string]string{"code": "PermissionInUse"})(w, r)
+						return
+					}
+					jsonHandler(http.StatusOK, nil)(w, r)
+				},
+				"GET /v2/service-accounts": jsonHandler(http.StatusOK, []GetServiceAccountResponse{{Id: "sa-1"}, {Id: "sa-2"}}),
+				"GET /v2/service-accounts/sa-1": func(w http.ResponseWriter, r *http.Request) {
+					permissions := []string{"other-id"}
+					if attached {
+						permissions = append(permissions, "permission-id")
+					}
+					jsonHandler(http.StatusOK, GetServiceAccountResponse{Id: "sa-1", Name: "sa-1", Permissions: permissions})(w, r)
+				},
+				"GET /v2/service-accounts/sa-2": jsonHandler(http.StatusOK, GetServiceAccountResponse{Id: "sa-2", Name: "sa-2", Permissions: []string{"other-id"}}),
+				"PUT /v2/service-accounts/sa-1": func(w http.ResponseWriter, r *http.Request) {
+					attached = false
+					jsonHandler(http.StatusOK, nil)(w, r)
+				},
+			})
+
+			d := schema.TestResourceDataRaw(t, ResourcePermission().Schema, map[string]interface{}{
+				"description":       "description",
+				"actions":           "read-only",
+				"all_buckets":       true,
+				"detach_on_destroy": testCase.DetachOnDestroy,
+			})
+			d.SetId("permission-id")
+
+			err := resourcePermissionDelete(d, Client{AccountAPIClient: &AuthData{}})
+			if testCase.ExpectedError == "" && err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if testCase.ExpectedError != "" && (err == nil || !strings.Contains(err.Error(), testCase.ExpectedError)) {
+				t.Fatalf("got error %v, expected it to contain %q", err, testCase.ExpectedError)
+			}
+
+			for key, expected := range testCase.ExpectedCalls {
+				if got := api.count(key); got != expected {
+					t.Errorf("got %d calls to %s, expected %d", got, key, expected)
+				}
+			}
+		})
+	}
+}
+
+func TestResourcePermissionStateUpgradeV0(t *testing.T) {
+	testCases := []struct {
+		Name     string
+		RawState map[string]interface{}
+		Expected map[string]interface{}
+	}{
+		{
+			Name: "bucket names",
+			RawState: map[string]interface{}{
+				"id":      "permission-id",
+				"type":    "bucket-names",
+				"buckets": []interface{}{"b", "a"},
+			},
+			Expected: map[string]interface{}{
+				"id":      "permission-id",
+				"type":    "bucket-names",
+				"buckets": []interface{}{"b", "a"},
+			},
+		},
+		{
+			Name: "duplicate bucket names",
+			RawState: map[string]interface{}{
+				"id":      "permission-id",
+				"type":    "bucket-names",
+				"buckets": []interface{}{"a", "b", "a"},
+			},
+			Expected: map[string]interface{}{
+				"id":      "permission-id",
+				"type":    "bucket-names",
+				"buckets": []interface{}{"a", "b"},
+			},
+		},
+		{
+			Name: "no buckets",
+			RawState: map[string]interface{}{
+				"id":            "permission-id",
+				"type":          "bucket-prefix",
+				"bucket_prefix": "logs-",
+				"buckets":       nil,
+			},
+			Expected: map[string]interface{}{
+				"id":            "permission-id",
+				"type":          "bucket-prefix",
+				"bucket_prefix": "logs-",
+				"buckets":       nil,
+			},
+		},
+	}
+
+	defaults := map[string]interface{}{
+		"validate_buckets":       false,
+		"warn_on_bucket_drift":   false,
+		"replace_on_type_change": true,
+		"adopt_existing":         false,
+		"detach_on_destroy":      false,
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			for key, v := range defaults {
+				testCase.Expected[key] = v
+			}
+
+			got, err := resourcePermissionStateUpgradeV0(context.Background(), testCase.RawState, nil)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if !reflect.DeepEqual(got, testCase.Expected) {
+				t.Errorf("got %v, expected %v", got, testCase.Expected)
+			}
+		})
+	}
+}
+
+func TestPermissionDifferences(t *testing.T) {
+	policy := `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":["s3:GetObject"],"Resource":"arn:aws:s3:::my-bucket/*"}]}`
+
+	testCases := []struct {
+		Name     string
+		Existing *GetPermissionResponse
+		Input    *Permission
+		Expected []string
+	}{
+		{
+			Name:     "same buckets in another order",
+			Existing: &GetPermissionResponse{Description: "d", Type: "bucket-names", Actions: "read-only", Buckets: []string{"b", "a"}},
+			Input:    &Permission{Description: "d", Type: "bucket-names", Actions: "read-only", Buckets: []string{"a", "b"}},
+		},
+		{
+			Name:     "other buckets and actions",
+			Existing: &GetPermissionResponse{Description: "d", Type: "bucket-names", Actions: "all-operations", Buckets: []string{"a"}},
+			Input:    &Permission{Description: "d", Type: "bucket-names", Actions: "read-only", Buckets: []string{"a", "b"}},
+			Expected: []string{"actions", "buckets"},
+		},
+		{
+			Name:     "other type",
+			Existing: &GetPermissionResponse{Description: "other", Type: "all-buckets", Actions: "read-only"},
+			Input:    &Permission{Description: "d", Type: "bucket-prefix", Actions: "read-only", Prefix: "logs-"},
+			Expected: []string{"description", "type"},
+		},
+		{
+			Name:     "other prefix",
+			Existing: &GetPermissionResponse{Description: "d", Type: "bucket-prefix", Actions: "read-only", Prefix: "data-"},
+			Input:    &Permission{Description: "d", Type: "bucket-prefix", Actions: "read-only", Prefix: "logs-"},
+			Expected: []string{"bucket_prefix"},
+		},
+		{
+			Name:     "equivalent escaped policy",
+			Existing: &GetPermissionResponse{Description: "d", Type: "policy", Policy: url.QueryEscape(policy)},
+			Input:    &Permission{Description: "d", Type: "policy", Policy: `{"Statement":[{"Action":"s3:GetObject","Effect":"Allow","Resource":"arn:aws:s3:::my-bucket/*"}],"Version":"2012-10-17"}`},
+		},
+		{
+			Name:     "other policy",
+			Existing: &GetPermissionResponse{Description: "d", Type: "policy", Policy: policy},
+			Input:    &Permission{Description: "d", Type: "policy", Policy: `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":"s3:PutObject","Resource":"arn:aws:s3:::my-bucket/*"}]}`},
+			Expected: []string{"policy"},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			got, err := permissionDifferences(testCase.Existing, testCase.Input)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if !reflect.DeepEqual(got, testCase.Expected) {
+				t.Errorf("got %v, expected %v", got, testCase.Expected)
+			}
+		})
+	}
+}
+
+func TestResourcePermissionUpgradeBaselineState(t *testing.T) {
+	// state written by the provider before the schema versioning.
+	rawState := `{
+		"actions": "read-only",
+		"all_buckets": null,
+		"bucket_prefix": null,
+		"buckets": ["bucket-b", "bucket-a", "bucket-b"],
+		"description": "description",
+		"id": "permission-id",
+		"name": "terraform-20230101000000000000000001",
+		"name_prefix": "",
+		"policy": null,
+		"ready_state": true,
+		"type": "bucket-names"
+	}`
+
+	state := upgradeBaselineState(t, "lyvecloud_permission", resourcePermissionV0(), rawState)
+
+	expected := cty.SetVal([]cty.Value{cty.StringVal("bucket-a"), cty.StringVal("bucket-b")})
+	if got := state.GetAttr("buckets"); !got.RawEquals(expected) {
+		t.Errorf("got buckets %#v, expected %#v", got, expected)
+	}
+
+	for name, expected := range map[string]string{"id": "permission-id", "type": "bucket-names", "actions": "read-only"} {
+		if got := state.GetAttr(name); !got.RawEquals(cty.StringVal(expected)) {
+			t.Errorf("got %s %#v, expected %q", name, got, expected)
+		}
+	}
+
+	planUpgradedState(t, "lyvecloud_permission", resourcePermissionV0(), state)
+}
@@ -1,9 +1,16 @@
 package lyvecloud
 
 import (
+	"context"
 	"fmt"
 	"log"
+	"reflect"
+	"sort"
+	"strings"
 
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
@@ -19,6 +26,26 @@ func ResourceServiceAccount() *schema.Resource {
 		Importer: &schema.ResourceImporter{
 			StateContext: schema.ImportStatePassthroughContext,
 		},
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(serviceAccountCreateTimeout),
+		},
+
+		SchemaVersion: 1,
+		StateUpgraders: []schema.StateUpgrader{
+			{
+				Type:    resourceServiceAccountV0().CoreConfigSchema().ImpliedType(),
+				Upgrade: resourceServiceAccountStateUpgradeV0,
+				Version: 0,
+			},
+		},
+
+		CustomizeDiff: customdiff.Sequence(
+			resourceServiceAccountPermissionNamesCustomizeDiff,
+			resourceServiceAccountPermissionBlocksCustomizeDiff,
+			resourceServiceAccountAdoptCustomizeDiff,
+		),
+
 		Schema: map[string]*schema.Schema{
 			"name": {
 				Type:         schema.TypeString,
@@ -30,8 +57,88 @@ func ResourceServiceAccount() *schema.Resource {
 				Optional: true,
 			},
 			"permissions": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Computed: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Set:           schema.HashString,
+				ConflictsWith: []string{"permission_names"},
+				AtLeastOneOf:  []string{"permissions", "permission_names", "permission"},
+			},
+			"permission_names": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Computed: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Set:           schema.HashString,
+				ConflictsWith: []string{"permissions"},
+				AtLeastOneOf:  []string{"permissions", "permission_names", "permission"},
+			},
+			"permission": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"description": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"actions": {
+							Type:     schema.TypeString,
+							Optional: true,
+							ValidateFunc: validation.StringInSlice([]string{
+								"all-operations",
+								"read-only",
+								"write-only",
+							}, false),
+						},
+						"all_buckets": {
+							Type:     schema.TypeBool,
+							Optional: true,
+						},
+						"bucket_prefix": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"buckets": {
+							Type:     schema.TypeList,
+							Optional: true,
+							Elem: &schema.Schema{
+								Type: schema.TypeString,
+							},
+						},
+						"policy": {
+							Type:             schema.TypeString,
+							Optional:         true,
+							ValidateDiagFunc: validatePermissionPolicyDiag,
+							DiffSuppressFunc: suppressEquivalentPolicyDiffs,
+							StateFunc: func(v interface{}) string {
+								json, _ := NormalizeJsonString(v)
+								return json
+							},
+						},
+						"id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"type": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+			"managed_permission_ids": {
 				Type:     schema.TypeList,
-				Required: true,
+				Computed: true,
 				Elem: &schema.Schema{
 					Type: schema.TypeString,
 				},
@@ -56,6 +163,15 @@ func ResourceServiceAccount() *schema.Resource {
 				Type:     schema.TypeBool,
 				Computed: true,
 			},
+			"adopt_existing": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"verify_credentials": {
+				Type:     schema.TypeBool,
+				Optional: true,
+			},
 		},
 	}
 }
@@ -65,25 +181,48 @@ func resourceServiceAccountCreate(d *schema.ResourceData, meta interface{}) erro
 		return fmt.Errorf("credentials for account api are missing")
 	}
 
+	if err := CheckReadOnly(meta.(Client), "create service account"); err != nil {
+		return err
+	}
+
 	conn := *meta.(Client).AccountAPIClient
 
 	name := d.Get("name").(string)
 	description := d.Get("description").(string)
-	permissionsList := d.Get("permissions").([]interface{})
 
-	permissions := []string{}
-	for _, v := range permissionsList {
-		permissions = append(permissions, v.(string))
+	if d.Get("verify_credentials").(bool) && CheckCredentials(S3, meta.(Client)) {
+		return fmt.Errorf("verify_credentials requires the s3 block of the provider, whose endpoint and region the credentials are verified against")
 	}
 
+	permissions, err := serviceAccountPermissions(&conn, d)
+	if err != nil {
+		return err
+	}
+
+	managed, managedIds, err := createServiceAccountManagedPermissions(&conn, name, d.Get("permission").([]interface{}))
+	if err != nil {
+		return err
+	}
+	d.Set("permission", managed)
+
 	serviceAccountInput := ServiceAccount{
 		Name:        name,
 		Description: description,
-		Permissions: permissions,
+		Permissions: append(permissions, managedIds...),
 	}
 
 	resp, err := conn.CreateServiceAccount(&serviceAccountInput)
+	if err != nil && d.Get("adopt_existing").(bool) {
+		log.Printf("[WARN] Error creating service account (%s), looking for an existing service account to adopt: %s", name, err)
+		resp, err = adoptExistingServiceAccount(&conn, &serviceAccountInput, err)
+		if err == nil {
+			log.Printf("[WARN] Adopted service account (%s), its access_key and secret can't be retrieved and are left empty", resp.ID)
+		}
+	}
 	if err != nil {
+		if err := deleteServiceAccountManagedPermissions(&conn, managedIds); err != nil {
+			log.Printf("[WARN] Unable to clean up managed permissions: %s", err)
+		}
 		return fmt.Errorf("error creating service account: %w", err)
 	}
 
@@ -92,6 +231,15 @@ func resourceServiceAccountCreate(d *schema.ResourceData, meta interface{}) erro
 	d.Set("access_key", resp.Accesskey)
 	d.Set("secret", resp.Secret)
 
+	if d.Get("verify_credentials").(bool) {
+		s3Config := meta.(Client).S3Client.Config
+		if resp.Accesskey == "" {
+			log.Printf("[WARN] Keys of service account (%s) are not known, skipping their verification", d.Id())
+		} else if err := verifyServiceAccountCredentials(context.Background(), &conn, aws.StringValue(s3Config.Region), aws.StringValue(s3Config.Endpoint), resp.Accesskey, resp.Secret, serviceAccountInput.Permissions, d.Timeout(schema.TimeoutCreate)); err != nil {
+			return fmt.Errorf("error verifying service account (%s) credentials: %w", d.Id(), err)
+		}
+	}
+
 	return resourceServiceAccountRead(d, meta)
 }
 
@@ -106,7 +254,7 @@ func resourceServiceAccountRead(d *schema.ResourceData, meta interface{}) error
 
 	resp, err := conn.GetServiceAccount(serviceAccountId)
 
-	if !d.IsNewResource() && err.Error() == ServiceAccountNotFound {
+	if !d.IsNewResource() && err != nil && err.Error() == ServiceAccountNotFound {
 		log.Printf("[WARN] Service Account (%s) not found, removing from state", d.Id())
 		d.SetId("")
 		return nil
@@ -116,13 +264,42 @@ func resourceServiceAccountRead(d *schema.ResourceData, meta interface{}) error
 		return fmt.Errorf("error reading service account (%s): %w", serviceAccountId, err)
 	}
 
+	managed, managedIds, err := readServiceAccountManagedPermissions(&conn, d.Get("permission").([]interface{}))
+	if err != nil {
+		return fmt.Errorf("error reading service account (%s) managed permissions: %w", serviceAccountId, err)
+	}
+
+	if err := d.Set("permission", managed); err != nil {
+		return fmt.Errorf("error setting permission: %w", err)
+	}
+	d.Set("managed_permission_ids", managedIds)
+
+	// permissions only lists the permissions that are not managed by the permission blocks.
+	permissions := []string{}
+	for _, id := range resp.Permissions {
+		if !stringInSlice(id, managedIds) {
+			permissions = append(permissions, id)
+		}
+	}
+
 	d.Set("id", resp.Id)
 	d.Set("name", resp.Name)
 	d.Set("description", resp.Description)
 	d.Set("ready_state", resp.ReadyState)
-	d.Set("permissions", resp.Permissions)
+	d.Set("permissions", permissions)
 	d.Set("enabled", resp.Enabled)
 
+	// permission names are only refreshed when they are used, saving a ListPermissions call on every read otherwise.
+	// They are always set, as a null value would be planned as a change of the computed attribute.
+	permissionNames := []string{}
+	if d.Get("permission_names").(*schema.Set).Len() > 0 {
+		permissionNames, err = permissionNamesByIds(&conn, permissions)
+		if err != nil {
+			return fmt.Errorf("error reading service account (%s) permission names: %w", serviceAccountId, err)
+		}
+	}
+	d.Set("permission_names", permissionNames)
+
 	return nil
 }
 
@@ -131,28 +308,66 @@ func resourceServiceAccountUpdate(d *schema.ResourceData, meta interface{}) erro
 		return fmt.Errorf("credentials for account api are missing")
 	}
 
+	if err := CheckReadOnly(meta.(Client), "update service account"); err != nil {
+		return err
+	}
+
 	conn := *meta.(Client).AccountAPIClient
 
 	serviceAccountId := d.Id()
 
+	accountAPIMutexKV.Lock(serviceAccountMutexKey(serviceAccountId))
+	defer accountAPIMutexKV.Unlock(serviceAccountMutexKey(serviceAccountId))
+
 	name := d.Get("name").(string)
 	description := d.Get("description").(string)
-	permissionsList := d.Get("permissions").([]interface{})
 
-	permissions := []string{}
-	for _, v := range permissionsList {
-		permissions = append(permissions, v.(string))
+	permissions, err := serviceAccountPermissions(&conn, d)
+	if err != nil {
+		return err
+	}
+
+	managedIds, err := convertPermissionsList(d.Get("managed_permission_ids").([]interface{}))
+	if err != nil {
+		return err
+	}
+
+	// the permission blocks are only recorded in the state once the service account is updated,
+	// a failed update keeps the previous blocks in the state.
+	d.Partial(true)
+
+	var managed []interface{}
+	var createdIds, obsoleteIds []string
+	if d.HasChange("permission") {
+		o, n := d.GetChange("permission")
+		previousIds := managedIds
+		managed, managedIds, obsoleteIds, err = updateServiceAccountManagedPermissions(&conn, name, o.([]interface{}), n.([]interface{}))
+		if err != nil {
+			return err
+		}
+
+		for _, id := range managedIds {
+			if !stringInSlice(id, previousIds) {
+				createdIds = append(createdIds, id)
+			}
+		}
 	}
 
 	updateServiceAccountInput := ServiceAccount{
 		Name:        name,
 		Description: description,
-		Permissions: permissions,
+		Permissions: append(permissions, managedIds...),
 	}
 
-	_, err := conn.UpdateServiceAccount(serviceAccountId, &updateServiceAccountInput)
+	_, err = conn.UpdateServiceAccount(serviceAccountId, &updateServiceAccountInput)
+	if err != nil {
+		// permissions created for this update are not attached to anything, nor recorded in the state.
+		if err := deleteServiceAccountManagedPermissions(&conn, createdIds); err != nil {
+			log.Printf("[WARN] Unable to clean up managed permissions: %s", err)
+		}
+	}
 
-	if !d.IsNewResource() && err.Error() == ServiceAccountNotFound {
+	if !d.IsNewResource() && err != nil && err.Error() == ServiceAccountNotFound {
 		log.Printf("[WARN] Service Account (%s) not found, removing from state", d.Id())
 		d.SetId("")
 		return nil
@@ -162,6 +377,16 @@ func resourceServiceAccountUpdate(d *schema.ResourceData, meta interface{}) erro
 		return fmt.Errorf("error updating service account: %w", err)
 	}
 
+	if managed != nil {
+		d.Set("permission", managed)
+	}
+	d.Partial(false)
+
+	// permissions replaced or removed from the configuration can only be deleted once detached.
+	if err := deleteServiceAccountManagedPermissions(&conn, obsoleteIds); err != nil {
+		return fmt.Errorf("error updating service account (%s): %w", serviceAccountId, err)
+	}
+
 	return resourceServiceAccountRead(d, meta)
 }
 
@@ -170,6 +395,10 @@ func resourceServiceAccountDelete(d *schema.ResourceData, meta interface{}) erro
 		return fmt.Errorf("credentials for account api are missing")
 	}
 
+	if err := CheckReadOnly(meta.(Client), "delete service account"); err != nil {
+		return err
+	}
+
 	conn := *meta.(Client).AccountAPIClient
 
 	_, err := conn.DeleteServiceAccount(d.Id())
@@ -177,5 +406,531 @@ func resourceServiceAccountDelete(d *schema.ResourceData, meta interface{}) erro
 		return fmt.Errorf("error deleting service account: %w", err)
 	}
 
+	managedIds, err := convertPermissionsList(d.Get("managed_permission_ids").([]interface{}))
+	if err != nil {
+		return err
+	}
+	if err := deleteServiceAccountManagedPermissions(&conn, managedIds); err != nil {
+		return fmt.Errorf("error deleting service account: %w", err)
+	}
+
+	return nil
+}
+
+// resourceServiceAccountPermissionBlocksCustomizeDiff checks at plan time that each permission block sets exactly one of
+// buckets, bucket_prefix, all_buckets or policy, and actions unless policy is set. Blocks with unknown values are
+// checked when they are known, at the latest when the plan is applied.
+func resourceServiceAccountPermissionBlocksCustomizeDiff(_ context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if !d.HasChange("permission") {
+		return nil
+	}
+
+	var errs *multierror.Error
+	for i, v := range d.Get("permission").([]interface{}) {
+		known := true
+		for _, key := range []string{"actions", "all_buckets", "bucket_prefix", "buckets", "policy"} {
+			known = known && d.NewValueKnown(fmt.Sprintf("permission.%d.%s", i, key))
+		}
+
+		tfMap, ok := v.(map[string]interface{})
+		if !known || !ok {
+			continue
+		}
+
+		if _, err := expandServiceAccountManagedPermission(tfMap, "", d.Get("name").(string)); err != nil {
+			errs = multierror.Append(errs, fmt.Errorf("permission block %d: %w", i+1, err))
+		}
+	}
+
+	return errs.ErrorOrNil()
+}
+
+// resourceServiceAccountPermissionNamesCustomizeDiff resolves permission_names to permission IDs at plan time,
+// failing for names that don't exist. Unknown names leave permissions unknown until they are resolved at apply.
+func resourceServiceAccountPermissionNamesCustomizeDiff(_ context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if !d.HasChange("permission_names") {
+		return nil
+	}
+
+	if rawConfig := d.GetRawConfig(); !rawConfig.IsNull() && rawConfig.GetAttr("permission_names").IsNull() {
+		return nil
+	}
+
+	if !d.NewValueKnown("permission_names") {
+		return d.SetNewComputed("permissions")
+	}
+
+	client, ok := meta.(Client)
+	if !ok || CheckCredentials(AccountAPI, client) {
+		return d.SetNewComputed("permissions")
+	}
+
+	names, err := convertPermissionsList(d.Get("permission_names").(*schema.Set).List())
+	if err != nil {
+		return err
+	}
+
+	permissions, unresolved, err := resolvePermissionNames(client.AccountAPIClient, names)
+	if err != nil {
+		return err
+	}
+
+	if len(unresolved) > 0 {
+		return fmt.Errorf("permission_names not found: %s; permissions created in the same configuration must be set in permissions by ID", strings.Join(unresolved, ", "))
+	}
+
+	return d.SetNew("permissions", permissions)
+}
+
+// findServiceAccountByName returns the service account with the given name, failing if several service accounts have it.
+func findServiceAccountByName(conn *AuthData, name string) (*GetServiceAccountResponse, error) {
+	serviceAccounts, err := conn.ListServiceAccounts()
+	if err != nil {
+		return nil, fmt.Errorf("error listing service accounts: %w", err)
+	}
+
+	var found *GetServiceAccountResponse
+	var ids []string
+	for i := range serviceAccounts {
+		if serviceAccounts[i].Name == name {
+			found = &serviceAccounts[i]
+			ids = append(ids, serviceAccounts[i].Id)
+		}
+	}
+
+	if len(ids) > 1 {
+		return nil, fmt.Errorf("multiple service accounts named %s: %s", name, strings.Join(ids, ", "))
+	}
+
+	if found == nil {
+		return nil, fmt.Errorf("service account (%s) not found", name)
+	}
+
+	return found, nil
+}
+
+// resourceServiceAccountAdoptCustomizeDiff rejects adopt_existing on a new service account with permission blocks,
+// as their permissions are created with new IDs, which an existing service account can't be attached to.
+func resourceServiceAccountAdoptCustomizeDiff(_ context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if d.Id() != "" || !d.Get("adopt_existing").(bool) {
+		return nil
+	}
+
+	if len(d.Get("permission").([]interface{})) > 0 {
+		return fmt.Errorf("adopt_existing can't be used with permission blocks, whose new permissions can't match an existing service account")
+	}
+
 	return nil
 }
+
+// adoptExistingServiceAccount returns the ID of the service account named as the input, if it matches the input,
+// so that a create that failed or timed out after the service account was committed can complete.
+// The keys of an adopted service account can't be retrieved, so the response holds none.
+// createErr is returned if there is no such service account.
+func adoptExistingServiceAccount(conn *AuthData, input *ServiceAccount, createErr error) (*ServiceAccountResponse, error) {
+	serviceAccount, err := findServiceAccountByName(conn, input.Name)
+	if err != nil {
+		log.Printf("[DEBUG] No service account to adopt: %s", err)
+		return nil, createErr
+	}
+
+	existing, err := conn.GetServiceAccount(serviceAccount.Id)
+	if err != nil {
+		return nil, fmt.Errorf("%w; error reading service account (%s) to adopt: %s", createErr, serviceAccount.Id, err)
+	}
+
+	if differences := serviceAccountDifferences(existing, input); len(differences) > 0 {
+		return nil, fmt.Errorf("%w; service account (%s) named %s exists but can't be adopted, its %s differ", createErr, serviceAccount.Id, input.Name, strings.Join(differences, ", "))
+	}
+
+	log.Printf("[INFO] Adopting existing service account (%s) named %s, its keys are not known", serviceAccount.Id, input.Name)
+
+	return &ServiceAccountResponse{ID: serviceAccount.Id}, nil
+}
+
+// serviceAccountDifferences returns the names of the fields in which the existing service account differs from the input.
+func serviceAccountDifferences(existing *GetServiceAccountResponse, input *ServiceAccount) []string {
+	var differences []string
+
+	if existing.Description != input.Description {
+		differences = append(differences, "description")
+	}
+
+	if !stringSetsEqual(existing.Permissions, input.Permissions) {
+		differences = append(differences, "permissions")
+	}
+
+	return differences
+}
+
+// serviceAccountPermissions returns the permission IDs to attach to the service account,
+// resolving permission_names if they are set in the configuration.
+func serviceAccountPermissions(conn *AuthData, d *schema.ResourceData) ([]string, error) {
+	if rawConfig := d.GetRawConfig(); !rawConfig.IsNull() && !rawConfig.GetAttr("permission_names").IsNull() {
+		names, err := convertPermissionsList(d.Get("permission_names").(*schema.Set).List())
+		if err != nil {
+			return nil, err
+		}
+
+		return permissionIdsByNames(conn, names)
+	}
+
+	return convertPermissionsList(d.Get("permissions").(*schema.Set).List())
+}
+
+// permissionIdsByNames resolves permission names to permission IDs, failing if any name is not found.
+func permissionIdsByNames(conn *AuthData, names []string) ([]string, error) {
+	ids, unresolved, err := resolvePermissionNames(conn, names)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(unresolved) > 0 {
+		return nil, fmt.Errorf("permissions not found: %s", strings.Join(unresolved, ", "))
+	}
+
+	return ids, nil
+}
+
+// resolvePermissionNames resolves permission names to permission IDs, also returning the sorted names that are not found.
+func resolvePermissionNames(conn *AuthData, names []string) ([]string, []string, error) {
+	permissions, err := conn.ListPermissions()
+	if err != nil {
+		return nil, nil, fmt.Errorf("error listing permissions: %w", err)
+	}
+
+	idByName := make(map[string]string, len(permissions))
+	for _, permission := range permissions {
+		idByName[permission.Name] = permission.Id
+	}
+
+	ids := []string{}
+	var unresolved []string
+	for _, name := range names {
+		id, ok := idByName[name]
+		if !ok {
+			unresolved = append(unresolved, name)
+			continue
+		}
+		ids = append(ids, id)
+	}
+
+	sort.Strings(unresolved)
+
+	return ids, unresolved, nil
+}
+
+// permissionNamesByIds returns the names of the given permission IDs.
+func permissionNamesByIds(conn *AuthData, ids []string) ([]string, error) {
+	permissions, err := conn.ListPermissions()
+	if err != nil {
+		return nil, fmt.Errorf("error listing permissions: %w", err)
+	}
+
+	nameById := make(map[string]string, len(permissions))
+	for _, permission := range permissions {
+		nameById[permission.Id] = permission.Name
+	}
+
+	names := []string{}
+	for _, id := range ids {
+		if name, ok := nameById[id]; ok {
+			names = append(names, name)
+		}
+	}
+
+	return names, nil
+}
+
+func convertPermissionsList(permissionsList []interface{}) ([]string, error) {
+	permissions := []string{}
+	for _, v := range permissionsList {
+		str, ok := v.(string)
+		if !ok {
+			return nil, fmt.Errorf("error converting permission: expected string, got %T", v)
+		}
+		permissions = append(permissions, str)
+	}
+	return permissions, nil
+}
+
+// createServiceAccountManagedPermissions creates the permissions of the permission blocks,
+// returning the blocks with their computed attributes and the created permission IDs.
+func createServiceAccountManagedPermissions(conn *AuthData, serviceAccountName string, blocks []interface{}) ([]interface{}, []string, error) {
+	managed := make([]interface{}, 0, len(blocks))
+	ids := []string{}
+
+	for _, v := range blocks {
+		tfMap, err := createServiceAccountManagedPermission(conn, serviceAccountName, v.(map[string]interface{}))
+		if err != nil {
+			if err := deleteServiceAccountManagedPermissions(conn, ids); err != nil {
+				log.Printf("[WARN] Unable to clean up managed permissions: %s", err)
+			}
+			return nil, nil, err
+		}
+
+		managed = append(managed, tfMap)
+		ids = append(ids, tfMap["id"].(string))
+	}
+
+	return managed, ids, nil
+}
+
+// updateServiceAccountManagedPermissions reconciles the permissions of the old permission blocks with the new ones by position.
+// Permissions whose type changes are replaced, as some Account API deployments reject an update to another type.
+// The returned obsolete IDs must be deleted once detached from the service account. On error, the permissions
+// created so far are deleted, as they aren't attached to the service account nor recorded in the state.
+func updateServiceAccountManagedPermissions(conn *AuthData, serviceAccountName string, oldBlocks, newBlocks []interface{}) ([]interface{}, []string, []string, error) {
+	managed := make([]interface{}, 0, len(newBlocks))
+	ids := []string{}
+	obsolete := []string{}
+	created := []string{}
+
+	fail := func(err error) ([]interface{}, []string, []string, error) {
+		if err := deleteServiceAccountManagedPermissions(conn, created); err != nil {
+			log.Printf("[WARN] Unable to clean up managed permissions: %s", err)
+		}
+		return nil, nil, nil, err
+	}
+
+	for i, v := range newBlocks {
+		tfMap := v.(map[string]interface{})
+
+		var old map[string]interface{}
+		if i < len(oldBlocks) {
+			old = oldBlocks[i].(map[string]interface{})
+		}
+
+		if old == nil || old["id"].(string) == "" {
+			tfMap, err := createServiceAccountManagedPermission(conn, serviceAccountName, tfMap)
+			if err != nil {
+				return fail(err)
+			}
+
+			managed = append(managed, tfMap)
+			ids = append(ids, tfMap["id"].(string))
+			created = append(created, tfMap["id"].(string))
+			continue
+		}
+
+		oldId := old["id"].(string)
+		input, err := expandServiceAccountManagedPermission(tfMap, old["name"].(string), serviceAccountName)
+		if err != nil {
+			return fail(err)
+		}
+
+		if input.Type != old["type"].(string) {
+			tfMap, err := createServiceAccountManagedPermission(conn, serviceAccountName, tfMap)
+			if err != nil {
+				return fail(err)
+			}
+
+			managed = append(managed, tfMap)
+			ids = append(ids, tfMap["id"].(string))
+			created = append(created, tfMap["id"].(string))
+			obsolete = append(obsolete, oldId)
+			continue
+		}
+
+		oldInput, err := expandServiceAccountManagedPermission(old, old["name"].(string), serviceAccountName)
+		if err != nil || !reflect.DeepEqual(oldInput, input) {
+			if _, err := conn.UpdatePermission(oldId, input); err != nil {
+				return fail(fmt.Errorf("error updating managed permission (%s): %w", oldId, err))
+			}
+		}
+
+		tfMap["id"] = oldId
+		tfMap["name"] = input.Name
+		tfMap["type"] = input.Type
+		managed = append(managed, tfMap)
+		ids = append(ids, oldId)
+	}
+
+	for i := len(newBlocks); i < len(oldBlocks); i++ {
+		if id := oldBlocks[i].(map[string]interface{})["id"].(string); id != "" {
+			obsolete = append(obsolete, id)
+		}
+	}
+
+	return managed, ids, obsolete, nil
+}
+
+func createServiceAccountManagedPermission(conn *AuthData, serviceAccountName string, tfMap map[string]interface{}) (map[string]interface{}, error) {
+	input, err := expandServiceAccountManagedPermission(tfMap, "", serviceAccountName)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := conn.CreatePermission(input)
+	if err != nil {
+		return nil, fmt.Errorf("error creating managed permission: %w", err)
+	}
+
+	created := make(map[string]interface{}, len(tfMap))
+	for k, v := range tfMap {
+		created[k] = v
+	}
+	created["id"] = resp.ID
+	created["name"] = input.Name
+	created["type"] = input.Type
+
+	return created, nil
+}
+
+// readServiceAccountManagedPermissions refreshes the permission blocks from the Account API.
+// A permission deleted outside of Terraform is emptied, so that it is created again on the next apply.
+func readServiceAccountManagedPermissions(conn *AuthData, blocks []interface{}) ([]interface{}, []string, error) {
+	managed := make([]interface{}, 0, len(blocks))
+	ids := []string{}
+
+	for _, v := range blocks {
+		tfMap := v.(map[string]interface{})
+		id := tfMap["id"].(string)
+		if id == "" {
+			managed = append(managed, tfMap)
+			continue
+		}
+
+		resp, err := conn.GetPermission(id)
+		if err != nil && err.Error() == PermissionNotFound {
+			log.Printf("[WARN] Managed permission (%s) not found", id)
+			managed = append(managed, map[string]interface{}{
+				"id":            "",
+				"name":          "",
+				"type":          "",
+				"description":   "",
+				"actions":       "",
+				"all_buckets":   false,
+				"bucket_prefix": "",
+				"buckets":       []interface{}{},
+				"policy":        "",
+			})
+			continue
+		}
+
+		if err != nil {
+			return nil, nil, fmt.Errorf("error reading managed permission (%s): %w", id, err)
+		}
+
+		policy, err := unescape(resp.Policy)
+		if err != nil {
+			return nil, nil, fmt.Errorf("Error parsing policy: %s", err)
+		}
+
+		policyToSet, err := PolicyToSet(tfMap["policy"].(string), policy)
+		if err != nil {
+			return nil, nil, fmt.Errorf("Error setting policy: %s", err)
+		}
+
+		tfMap["name"] = resp.Name
+		tfMap["description"] = resp.Description
+		tfMap["type"] = resp.Type
+		tfMap["all_buckets"] = resp.Type == "all-buckets"
+		tfMap["policy"] = policyToSet
+
+		if resp.Type != "policy" {
+			tfMap["actions"] = resp.Actions
+		}
+
+		if resp.Type == "bucket-names" {
+			tfMap["buckets"] = resp.Buckets
+		}
+
+		if resp.Type == "bucket-prefix" {
+			tfMap["bucket_prefix"] = resp.Prefix
+		}
+
+		managed = append(managed, tfMap)
+		ids = append(ids, id)
+	}
+
+	return managed, ids, nil
+}
+
+// deleteServiceAccountManagedPermissions deletes the given permissions, ignoring those already deleted.
+func deleteServiceAccountManagedPermissions(conn *AuthData, ids []string) error {
+	var errs *multierror.Error
+
+	for _, id := range ids {
+		_, err := conn.DeletePermission(id)
+		if err != nil && err.Error() != PermissionNotFound {
+			errs = multierror.Append(errs, fmt.Errorf("error deleting managed permission (%s): %w", id, err))
+		}
+	}
+
+	return errs.ErrorOrNil()
+}
+
+// expandServiceAccountManagedPermission converts a permission block to a Permission. An empty name is
+// generated from the service account name.
+func expandServiceAccountManagedPermission(tfMap map[string]interface{}, name, serviceAccountName string) (*Permission, error) {
+	if name == "" {
+		name = NameWithSuffix("", serviceAccountName+"-")
+	}
+
+	description := tfMap["description"].(string)
+	if description == "" {
+		description = fmt.Sprintf("Managed by service account %s", serviceAccountName)
+	}
+
+	permission := &Permission{
+		Name:        name,
+		Description: description,
+		Actions:     tfMap["actions"].(string),
+	}
+
+	buckets, err := convertBucketsList(tfMap["buckets"].([]interface{}))
+	if err != nil {
+		return nil, fmt.Errorf("error reading buckets list: %w", err)
+	}
+
+	prefix := tfMap["bucket_prefix"].(string)
+	policy := tfMap["policy"].(string)
+
+	set := 0
+	if tfMap["all_buckets"].(bool) {
+		permission.Type = "all-buckets"
+		set++
+	}
+	if prefix != "" {
+		permission.Type = "bucket-prefix"
+		permission.Prefix = prefix
+		set++
+	}
+	if len(buckets) > 0 {
+		permission.Type = "bucket-names"
+		permission.Buckets = buckets
+		set++
+	}
+	if policy != "" {
+		permission.Type = "policy"
+		permission.Policy, err = NormalizeJsonString(policy)
+		if err != nil {
+			return nil, fmt.Errorf("policy (%s) is invalid JSON: %w", policy, err)
+		}
+		set++
+	}
+
+	if set != 1 {
+		return nil, fmt.Errorf("exactly one of the following keys must be used in a permission block: buckets/bucket_prefix/all_buckets/policy")
+	}
+
+	if permission.Type == "policy" && permission.Actions != "" {
+		return nil, fmt.Errorf("actions can't be used with policy in a permission block")
+	}
+
+	if permission.Type != "policy" && permission.Actions == "" {
+		return nil, fmt.Errorf("actions must be set with buckets/bucket_prefix/all_buckets in a permission block")
+	}
+
+	return permission, nil
+}
+
+func stringInSlice(s string, list []string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,74 @@
+package lyvecloud
+
+import (
+	"context"
+	"log"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// resourceServiceAccountV0 is the schema of lyvecloud_service_account released before the schema versioning,
+// where permissions is a required list.
+func resourceServiceAccountV0() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.StringLenBetween(0, 128),
+			},
+			"description": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"permissions": {
+				Type:     schema.TypeList,
+				Required: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"access_key": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"secret": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"ready_state": {
+				Type:     schema.TypeBool,
+				Computed: true,
+			},
+			"enabled": {
+				Type:     schema.TypeBool,
+				Computed: true,
+			},
+		},
+	}
+}
+
+// resourceServiceAccountStateUpgradeV0 migrates permissions from a list to a set, and sets the attributes added since
+// version 0 to the values Read would set, which would otherwise be planned as changes.
+func resourceServiceAccountStateUpgradeV0(_ context.Context, rawState map[string]interface{}, meta interface{}) (map[string]interface{}, error) {
+	if rawState == nil {
+		return nil, nil
+	}
+
+	log.Printf("[DEBUG] Upgrading service account state from version 0")
+
+	upgradeStateListToSet(rawState, "permissions")
+
+	upgradeStateDefaults(rawState, map[string]interface{}{
+		"permission_names":       []interface{}{},
+		"managed_permission_ids": []interface{}{},
+		"adopt_existing":         false,
+	})
+
+	return rawState, nil
+}
@@ -0,0 +1,178 @@
+package lyvecloud
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func ResourceServiceAccountPermissionAttachment() *schema.Resource {
+
+	return &schema.Resource{
+		Create: resourceServiceAccountPermissionAttachmentCreate,
+		Read:   resourceServiceAccountPermissionAttachmentRead,
+		Delete: resourceServiceAccountPermissionAttachmentDelete,
+
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceServiceAccountPermissionAttachmentImport,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"service_account_id": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"permission_id": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+		},
+	}
+}
+
+func resourceServiceAccountPermissionAttachmentCreate(d *schema.ResourceData, meta interface{}) error {
+	if CheckCredentials(AccountAPI, meta.(Client)) {
+		return fmt.Errorf("credentials for account api are missing")
+	}
+
+	if err := CheckReadOnly(meta.(Client), "attach permission to service account"); err != nil {
+		return err
+	}
+
+	conn := *meta.(Client).AccountAPIClient
+
+	serviceAccountId := d.Get("service_account_id").(string)
+	permissionId := d.Get("permission_id").(string)
+
+	accountAPIMutexKV.Lock(serviceAccountMutexKey(serviceAccountId))
+	defer accountAPIMutexKV.Unlock(serviceAccountMutexKey(serviceAccountId))
+
+	serviceAccount, err := conn.GetServiceAccount(serviceAccountId)
+	if err != nil {
+		return fmt.Errorf("error reading service account (%s): %w", serviceAccountId, err)
+	}
+
+	if !stringInSlice(permissionId, serviceAccount.Permissions) {
+		_, err = conn.UpdateServiceAccount(serviceAccountId, &ServiceAccount{
+			Name:        serviceAccount.Name,
+			Description: serviceAccount.Description,
+			Permissions: append(serviceAccount.Permissions, permissionId),
+		})
+		if err != nil {
+			return fmt.Errorf("error attaching permission (%s) to service account (%s): %w", permissionId, serviceAccountId, err)
+		}
+	}
+
+	d.SetId(serviceAccountId + SlashSeparator + permissionId)
+
+	return resourceServiceAccountPermissionAttachmentRead(d, meta)
+}
+
+func resourceServiceAccountPermissionAttachmentRead(d *schema.ResourceData, meta interface{}) error {
+	if CheckCredentials(AccountAPI, meta.(Client)) {
+		return fmt.Errorf("credentials for account api are missing")
+	}
+
+	conn := *meta.(Client).AccountAPIClient
+
+	serviceAccountId := d.Get("service_account_id").(string)
+	permissionId := d.Get("permission_id").(string)
+
+	serviceAccount, err := conn.GetServiceAccount(serviceAccountId)
+
+	if !d.IsNewResource() && err != nil && err.Error() == ServiceAccountNotFound {
+		log.Printf("[WARN] Service Account (%s) not found, removing attachment (%s) from state", serviceAccountId, d.Id())
+		d.SetId("")
+		return nil
+	}
+
+	if err != nil {
+		return fmt.Errorf("error reading service account (%s): %w", serviceAccountId, err)
+	}
+
+	if !d.IsNewResource() && !stringInSlice(permissionId, serviceAccount.Permissions) {
+		log.Printf("[WARN] Permission (%s) not attached to service account (%s), removing attachment (%s) from state", permissionId, serviceAccountId, d.Id())
+		d.SetId("")
+		return nil
+	}
+
+	return nil
+}
+
+func resourceServiceAccountPermissionAttachmentDelete(d *schema.ResourceData, meta interface{}) error {
+	if CheckCredentials(AccountAPI, meta.(Client)) {
+		return fmt.Errorf("credentials for account api are missing")
+	}
+
+	if err := CheckReadOnly(meta.(Client), "detach permission from service account"); err != nil {
+		return err
+	}
+
+	conn := *meta.(Client).AccountAPIClient
+
+	return detachServiceAccountPermission(&conn, d.Get("service_account_id").(string), d.Get("permission_id").(string))
+}
+
+// detachServiceAccountPermission removes the permission from the service account, if attached.
+func detachServiceAccountPermission(conn *AuthData, serviceAccountId, permissionId string) error {
+	accountAPIMutexKV.Lock(serviceAccountMutexKey(serviceAccountId))
+	defer accountAPIMutexKV.Unlock(serviceAccountMutexKey(serviceAccountId))
+
+	serviceAccount, err := conn.GetServiceAccount(serviceAccountId)
+	if err != nil && err.Error() == ServiceAccountNotFound {
+		return nil
+	}
+
+	if err != nil {
+		return fmt.Errorf("error reading service account (%s): %w", serviceAccountId, err)
+	}
+
+	if !stringInSlice(permissionId, serviceAccount.Permissions) {
+		return nil
+	}
+
+	permissions := []string{}
+	for _, id := range serviceAccount.Permissions {
+		if id != permissionId {
+			permissions = append(permissions, id)
+		}
+	}
+
+	_, err = conn.UpdateServiceAccount(serviceAccountId, &ServiceAccount{
+		Name:        serviceAccount.Name,
+		Description: serviceAccount.Description,
+		Permissions: permissions,
+	})
+	if err != nil && err.Error() != ServiceAccountNotFound {
+		return fmt.Errorf("error detaching permission (%s) from service account (%s): %w", permissionId, serviceAccountId, err)
+	}
+
+	return nil
+}
+
+func resourceServiceAccountPermissionAttachmentImport(_ context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	serviceAccountId, permissionId, err := parseAttachmentId(d.Id(), "service-account-id/permission-id")
+	if err != nil {
+		return nil, err
+	}
+
+	d.Set("service_account_id", serviceAccountId)
+	d.Set("permission_id", permissionId)
+
+	return []*schema.ResourceData{d}, nil
+}
+
+// parseAttachmentId splits the ID of an attachment resource into its two parts. format describes the expected ID in errors.
+func parseAttachmentId(id, format string) (string, string, error) {
+	parts := strings.SplitN(id, SlashSeparator, 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", fmt.Errorf("unexpected format of ID (%s), expected %s", id, format)
+	}
+
+	return parts[0], parts[1], nil
+}
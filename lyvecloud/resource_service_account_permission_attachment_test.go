@@ -0,0 +1,52 @@
+package lyvecloud
+
+import (
+	"testing"
+)
+
+func TestParseAttachmentId(t *testing.T) {
+	testCases := []struct {
+		Name        string
+		Id          string
+		First       string
+		Second      string
+		ExpectError bool
+	}{
+		{
+			Name:   "valid",
+			Id:     "sa-id/permission-id",
+			First:  "sa-id",
+			Second: "permission-id",
+		},
+		{
+			Name:        "missing separator",
+			Id:          "sa-id",
+			ExpectError: true,
+		},
+		{
+			Name:        "empty part",
+			Id:          "/permission-id",
+			ExpectError: true,
+		},
+		{
+			Name:        "empty",
+			ExpectError: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			first, second, err := parseAttachmentId(testCase.Id, "first/second")
+
+			if testCase.ExpectError && err == nil {
+				t.Fatal("expected error")
+			} else if !testCase.ExpectError && err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if first != testCase.First || second != testCase.Second {
+				t.Errorf("got (%q, %q), expected (%q, %q)", first, second, testCase.First, testCase.Second)
+			}
+		})
+	}
+}
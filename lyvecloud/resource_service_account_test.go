@@ -0,0 +1,452 @@
+package lyvecloud
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestResourceServiceAccountCustomizeDiff_permissionNames(t *testing.T) {
+	newFakeAccountAPI(t, map[string]http.HandlerFunc{
+		"GET /v2/permissions": jsonHandler(http.StatusOK, []GetPermissionResponse{{Id: "permission-id", Name: "existing"}}),
+	})
+
+	testCases := []struct {
+		Name            string
+		PermissionNames []interface{}
+		ExpectedId      string
+		ExpectedError   string
+	}{
+		{
+			Name:            "existing permission",
+			PermissionNames: []interface{}{"existing"},
+			ExpectedId:      "permission-id",
+		},
+		{
+			Name:            "unknown permissions",
+			PermissionNames: []interface{}{"existing", "missing-b", "missing-a"},
+			ExpectedError:   "permission_names not found: missing-a, missing-b",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			raw := map[string]interface{}{
+				"name":             "sa",
+				"permission_names": testCase.PermissionNames,
+			}
+
+			diff, err := ResourceServiceAccount().Diff(context.Background(), nil, terraform.NewResourceConfigRaw(raw), Client{AccountAPIClient: &AuthData{}})
+			if testCase.ExpectedError != "" {
+				if err == nil || !strings.Contains(err.Error(), testCase.ExpectedError) {
+					t.Fatalf("got error %v, expected it to contain %q", err, testCase.ExpectedError)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			attr := diff.Attributes["permissions."+strconv.Itoa(schema.HashString(testCase.ExpectedId))]
+			if attr == nil || attr.New != testCase.ExpectedId {
+				t.Errorf("got permissions %v, expected %s", diff.Attributes, testCase.ExpectedId)
+			}
+		})
+	}
+}
+
+func TestResourceServiceAccountRead_permissionNames(t *testing.T) {
+	testCases := []struct {
+		Name          string
+		Raw           map[string]interface{}
+		ExpectedNames []string
+		ExpectedLists int
+	}{
+		{
+			Name:          "permissions",
+			Raw:           map[string]interface{}{"name": "sa", "permissions": []interface{}{"permission-id"}},
+			ExpectedNames: []string{},
+		},
+		{
+			Name:          "permission names",
+			Raw:           map[string]interface{}{"name": "sa", "permission_names": []interface{}{"existing"}},
+			ExpectedNames: []string{"existing"},
+			ExpectedLists: 1,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			api := newFakeAccountAPI(t, map[string]http.HandlerFunc{
+				"GET /v2/service-accounts/sa-id": jsonHandler(http.StatusOK, GetServiceAccountResponse{Id: "sa-id", Name: "sa", Permissions: []string{"permission-id"}}),
+				"GET /v2/permissions":            jsonHandler(http.StatusOK, []GetPermissionResponse{{Id: "permission-id", Name: "existing"}}),
+			})
+
+			d := schema.TestResourceDataRaw(t, ResourceServiceAccount().Schema, testCase.Raw)
+			d.SetId("sa-id")
+
+			if err := resourceServiceAccountRead(d, Client{AccountAPIClient: &AuthData{}}); err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			// a permission_names missing from the state would be planned as a change.
+			if got := d.State().Attributes["permission_names.#"]; got != strconv.Itoa(len(testCase.ExpectedNames)) {
+				t.Errorf("got permission_names.# %q, expected %d", got, len(testCase.ExpectedNames))
+			}
+
+			names, err := convertPermissionsList(d.Get("permission_names").(*schema.Set).List())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(names, testCase.ExpectedNames) {
+				t.Errorf("got permission_names %v, expected %v", names, testCase.ExpectedNames)
+			}
+
+			if got := api.count("GET /v2/permissions"); got != testCase.ExpectedLists {
+				t.Errorf("got %d permission lists, expected %d", got, testCase.ExpectedLists)
+			}
+		})
+	}
+}
+
+func TestResourceServiceAccountCustomizeDiff_permissionBlocks(t *testing.T) {
+	testCases := []struct {
+		Name     string
+		Blocks   []interface{}
+		Expected string
+	}{
+		{
+			Name: "valid",
+			Blocks: []interface{}{
+				map[string]interface{}{"actions": "read-only", "buckets": []interface{}{"my-bucket"}},
+				map[string]interface{}{"policy": `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":"s3:GetObject","Resource":"*"}]}`},
+			},
+		},
+		{
+			Name: "multiple targets",
+			Blocks: []interface{}{
+				map[string]interface{}{"actions": "read-only", "buckets": []interface{}{"my-bucket"}},
+				map[string]interface{}{"actions": "read-only", "all_buckets": true, "bucket_prefix": "my-"},
+			},
+			Expected: "permission block 2: exactly one of the following keys must be used",
+		},
+		{
+			Name: "missing actions",
+			Blocks: []interface{}{
+				map[string]interface{}{"all_buckets": true},
+			},
+			Expected: "permission block 1: actions must be set",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			raw := map[string]interface{}{
+				"name":       "sa",
+				"permission": testCase.Blocks,
+			}
+
+			_, err := ResourceServiceAccount().Diff(context.Background(), nil, terraform.NewResourceConfigRaw(raw), nil)
+			if testCase.Expected == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %s", err)
+				}
+				return
+			}
+
+			if err == nil || !strings.Contains(err.Error(), testCase.Expected) {
+				t.Fatalf("got error %v, expected it to contain %q", err, testCase.Expected)
+			}
+		})
+	}
+}
+
+func TestResourceServiceAccountCustomizeDiff_adopt(t *testing.T) {
+	raw := map[string]interface{}{
+		"name":           "sa",
+		"permissions":    []interface{}{"permission-id"},
+		"adopt_existing": true,
+	}
+
+	if _, err := ResourceServiceAccount().Diff(context.Background(), nil, terraform.NewResourceConfigRaw(raw), nil); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	raw["permission"] = []interface{}{map[string]interface{}{"actions": "read-only", "all_buckets": true}}
+
+	_, err := ResourceServiceAccount().Diff(context.Background(), nil, terraform.NewResourceConfigRaw(raw), nil)
+	if err == nil || !strings.Contains(err.Error(), "adopt_existing can't be used with permission blocks") {
+		t.Fatalf("got error %v, expected adopt_existing to be rejected", err)
+	}
+}
+
+func TestUpdateServiceAccountManagedPermissions_cleanup(t *testing.T) {
+	created := 0
+	api := newFakeAccountAPI(t, map[string]http.HandlerFunc{
+		"POST /v2/permissions": func(w http.ResponseWriter, r *http.Request) {
+			created++
+			if created > 1 {
+				jsonHandler(http.StatusBadRequest, map[string]string{"code": "InvalidRequest"})(w, r)
+				return
+			}
+			jsonHandler(http.StatusOK, PermissionResponse{ID: "created-1"})(w, r)
+		},
+		"DELETE /v2/permissions/created-1": jsonHandler(http.StatusOK, nil),
+	})
+
+	block := func(bucket string) map[string]interface{} {
+		return map[string]interface{}{
+			"id":            "",
+			"name":          "",
+			"type":          "",
+			"description":   "",
+			"actions":       "read-only",
+			"all_buckets":   false,
+			"bucket_prefix": "",
+			"buckets":       []interface{}{bucket},
+			"policy":        "",
+		}
+	}
+
+	_, _, _, err := updateServiceAccountManagedPermissions(&AuthData{}, "sa", nil, []interface{}{block("a"), block("b")})
+	if err == nil || !strings.Contains(err.Error(), "InvalidRequest") {
+		t.Fatalf("got error %v, expected InvalidRequest", err)
+	}
+
+	if got := api.count("DELETE /v2/permissions/created-1"); got != 1 {
+		t.Errorf("got %d deletions of the created permission, expected 1", got)
+	}
+}
+
+func TestResourceServiceAccountUpdate_cleanup(t *testing.T) {
+	testCases := []struct {
+		Name          string
+		Code          string
+		ExpectedError string
+	}{
+		{
+			Name:          "update failed",
+			Code:          "InvalidRequest",
+			ExpectedError: "InvalidRequest",
+		},
+		{
+			Name: "service account not found",
+			Code: ServiceAccountNotFound,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			api := newFakeAccountAPI(t, map[string]http.HandlerFunc{
+				"POST /v2/permissions":             jsonHandler(http.StatusOK, PermissionResponse{ID: "created-1"}),
+				"DELETE /v2/permissions/created-1": jsonHandler(http.StatusOK, nil),
+				"PUT /v2/service-accounts/sa-id":   jsonHandler(http.StatusBadRequest, map[string]string{"code": testCase.Code}),
+			})
+
+			d := schema.TestResourceDataRaw(t, ResourceServiceAccount().Schema, map[string]interface{}{
+				"name":        "sa",
+				"description": "description",
+				"permission":  []interface{}{map[string]interface{}{"actions": "read-only", "buckets": []interface{}{"a"}}},
+			})
+			d.SetId("sa-id")
+
+			err := resourceServiceAccountUpdate(d, Client{AccountAPIClient: &AuthData{}})
+			if testCase.ExpectedError == "" && err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if testCase.ExpectedError != "" && (err == nil || !strings.Contains(err.Error(), testCase.ExpectedError)) {
+				t.Fatalf("got error %v, expected it to contain %q", err, testCase.ExpectedError)
+			}
+
+			if got := api.count("DELETE /v2/permissions/created-1"); got != 1 {
+				t.Errorf("got %d deletions of the created permission, expected 1", got)
+			}
+		})
+	}
+}
+
+func TestExpandServiceAccountManagedPermission(t *testing.T) {
+	block := func(m map[string]interface{}) map[string]interface{} {
+		tfMap := map[string]interface{}{
+			"description":   "",
+			"actions":       "",
+			"all_buckets":   false,
+			"bucket_prefix": "",
+			"buckets":       []interface{}{},
+			"policy":        "",
+		}
+		for k, v := range m {
+			tfMap[k] = v
+		}
+		return tfMap
+	}
+
+	testCases := []struct {
+		Name        string
+		Block       map[string]interface{}
+		Expected    Permission
+		ExpectError bool
+	}{
+		{
+			Name:  "buckets",
+			Block: block(map[string]interface{}{"actions": "read-only", "buckets": []interface{}{"a", "b"}}),
+			Expected: Permission{
+				Name:        "permission-name",
+				Description: "Managed by service account sa",
+				Type:        "bucket-names",
+				Actions:     "read-only",
+				Buckets:     []string{"a", "b"},
+			},
+		},
+		{
+			Name:  "policy",
+			Block: block(map[string]interface{}{"description": "custom", "policy": `{ "Version": "2012-10-17", "Statement": [] }`}),
+			Expected: Permission{
+				Name:        "permission-name",
+				Description: "custom",
+				Type:        "policy",
+				Policy:      `{"Statement":[],"Version":"2012-10-17"}`,
+			},
+		},
+		{
+			Name:        "no target",
+			Block:       block(map[string]interface{}{"actions": "read-only"}),
+			ExpectError: true,
+		},
+		{
+			Name:        "multiple targets",
+			Block:       block(map[string]interface{}{"actions": "read-only", "all_buckets": true, "bucket_prefix": "a"}),
+			ExpectError: true,
+		},
+		{
+			Name:        "missing actions",
+			Block:       block(map[string]interface{}{"all_buckets": true}),
+			ExpectError: true,
+		},
+		{
+			Name:        "actions with policy",
+			Block:       block(map[string]interface{}{"actions": "read-only", "policy": `{"Version":"2012-10-17"}`}),
+			ExpectError: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			got, err := expandServiceAccountManagedPermission(testCase.Block, "permission-name", "sa")
+
+			if testCase.ExpectError {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if !reflect.DeepEqual(*got, testCase.Expected) {
+				t.Errorf("got %+v, expected %+v", *got, testCase.Expected)
+			}
+		})
+	}
+}
+
+func TestResourceServiceAccountStateUpgradeV0(t *testing.T) {
+	rawState := map[string]interface{}{
+		"id":          "sa-id",
+		"name":        "sa",
+		"permissions": []interface{}{"permission-2", "permission-1", "permission-2"},
+	}
+
+	expected := map[string]interface{}{
+		"id":                     "sa-id",
+		"name":                   "sa",
+		"permissions":            []interface{}{"permission-2", "permission-1"},
+		"permission_names":       []interface{}{},
+		"managed_permission_ids": []interface{}{},
+		"adopt_existing":         false,
+	}
+
+	got, err := resourceServiceAccountStateUpgradeV0(context.Background(), rawState, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("got %v, expected %v", got, expected)
+	}
+}
+
+func TestServiceAccountDifferences(t *testing.T) {
+	testCases := []struct {
+		Name     string
+		Existing *GetServiceAccountResponse
+		Input    *ServiceAccount
+		Expected []string
+	}{
+		{
+			Name:     "same permissions in another order",
+			Existing: &GetServiceAccountResponse{Name: "sa", Description: "d", Permissions: []string{"p2", "p1"}},
+			Input:    &ServiceAccount{Name: "sa", Description: "d", Permissions: []string{"p1", "p2"}},
+		},
+		{
+			Name:     "other permissions",
+			Existing: &GetServiceAccountResponse{Name: "sa", Description: "d", Permissions: []string{"p1"}},
+			Input:    &ServiceAccount{Name: "sa", Description: "d", Permissions: []string{"p1", "p2"}},
+			Expected: []string{"permissions"},
+		},
+		{
+			Name:     "other description",
+			Existing: &GetServiceAccountResponse{Name: "sa", Description: "other", Permissions: []string{"p1"}},
+			Input:    &ServiceAccount{Name: "sa", Description: "d", Permissions: []string{"p1"}},
+			Expected: []string{"description"},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			got := serviceAccountDifferences(testCase.Existing, testCase.Input)
+
+			if !reflect.DeepEqual(got, testCase.Expected) {
+				t.Errorf("got %v, expected %v", got, testCase.Expected)
+			}
+		})
+	}
+}
+
+func TestResourceServiceAccountUpgradeBaselineState(t *testing.T) {
+	// state written by the provider before the schema versioning.
+	rawState := `{
+		"access_key": "access-key",
+		"description": "",
+		"enabled": true,
+		"id": "sa-id",
+		"name": "sa",
+		"permissions": ["permission-2", "permission-1", "permission-2"],
+		"ready_state": true,
+		"secret": "secret"
+	}`
+
+	state := upgradeBaselineState(t, "lyvecloud_service_account", resourceServiceAccountV0(), rawState)
+
+	expected := cty.SetVal([]cty.Value{cty.StringVal("permission-1"), cty.StringVal("permission-2")})
+	if got := state.GetAttr("permissions"); !got.RawEquals(expected) {
+		t.Errorf("got permissions %#v, expected %#v", got, expected)
+	}
+
+	for name, expected := range map[string]string{"id": "sa-id", "name": "sa", "access_key": "access-key", "secret": "secret"} {
+		if got := state.GetAttr(name); !got.RawEquals(cty.StringVal(expected)) {
+			t.Errorf("got %s %#v, expected %q", name, got, expected)
+		}
+	}
+
+	planUpgradedState(t, "lyvecloud_service_account", resourceServiceAccountV0(), state)
+}
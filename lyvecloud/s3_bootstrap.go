@@ -0,0 +1,215 @@
+package lyvecloud
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+const (
+	// bootstrapNamePrefix prefixes the names of the service accounts and permissions created for s3.from_account_api,
+	// followed by their UTC creation time in bootstrapTimeLayout and a unique suffix, as resource.PrefixedUniqueId names them.
+	bootstrapNamePrefix = "terraform-provider-lyvecloud-"
+	bootstrapTimeLayout = "20060102150405"
+
+	// bootstrapCredentialsTimeout bounds the wait for the keys of the bootstrap service account to be accepted.
+	bootstrapCredentialsTimeout = 2 * time.Minute
+
+	// bootstrapLeftoverAge is the age after which bootstrap service accounts and permissions are deleted as leftovers
+	// of a run killed before cleaning up. Runs lasting longer lose their S3 credentials.
+	bootstrapLeftoverAge = 24 * time.Hour
+
+	// shutdownTimeout bounds the cleanups of Shutdown, as Terraform kills the plugin about 2 seconds after stopping it.
+	shutdownTimeout = 1500 * time.Millisecond
+)
+
+var (
+	shutdownHooksMu sync.Mutex
+	shutdownHooks   []func()
+)
+
+// Shutdown runs the cleanups registered by the configured providers. It is called once the plugin stops serving,
+// and gives up after shutdownTimeout, leaving what isn't cleaned up to a later run.
+func Shutdown() {
+	shutdownHooksMu.Lock()
+	hooks := shutdownHooks
+	shutdownHooks = nil
+	shutdownHooksMu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for _, hook := range hooks {
+			hook()
+		}
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(shutdownTimeout):
+		log.Printf("[WARN] Cleanup didn't complete within %s, leftovers are deleted by a later run after %s", shutdownTimeout, bootstrapLeftoverAge)
+	}
+}
+
+func registerShutdownHook(hook func()) {
+	shutdownHooksMu.Lock()
+	defer shutdownHooksMu.Unlock()
+
+	shutdownHooks = append(shutdownHooks, hook)
+}
+
+// bootstrapCredentialsProvider creates the S3 credentials of s3.from_account_api on the first S3 API call,
+// so that configuring the provider without calling the S3 API creates none.
+type bootstrapCredentialsProvider struct {
+	conn     *AuthData
+	region   string
+	endpoint string
+	scope    *Permission
+
+	mu    sync.Mutex
+	value *credentials.Value
+}
+
+// Retrieve returns the keys of the bootstrap service account, creating it on the first call.
+func (p *bootstrapCredentialsProvider) Retrieve() (credentials.Value, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.value != nil {
+		return *p.value, nil
+	}
+
+	accessKey, secretKey, err := bootstrapS3Credentials(context.Background(), p.conn, p.region, p.endpoint, p.scope)
+	if err != nil {
+		return credentials.Value{}, err
+	}
+
+	p.value = &credentials.Value{
+		AccessKeyID:     accessKey,
+		SecretAccessKey: secretKey,
+		ProviderName:    "LyveCloudAccountAPI",
+	}
+	return *p.value, nil
+}
+
+// IsExpired returns true until the keys are created, they are valid until the provider shuts down.
+func (p *bootstrapCredentialsProvider) IsExpired() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.value == nil
+}
+
+// bootstrapS3Credentials creates a service account with a dedicated permission with all operations on the buckets
+// of scope and returns its keys once they are accepted by the S3 API. Both are deleted when the provider shuts down,
+// or by a later run once older than bootstrapLeftoverAge if this one is killed before.
+func bootstrapS3Credentials(ctx context.Context, conn *AuthData, region, endpoint string, scope *Permission) (string, string, error) {
+	deleteLeftoverBootstrapCredentials(conn, time.Now())
+
+	name := resource.PrefixedUniqueId(bootstrapNamePrefix)
+
+	permission, err := conn.CreatePermission(&Permission{
+		Name:        name,
+		Description: "Temporary S3 credentials of the Terraform provider, deleted when it shuts down",
+		Type:        scope.Type,
+		Actions:     "all-operations",
+		Buckets:     scope.Buckets,
+		Prefix:      scope.Prefix,
+	})
+	if err != nil {
+		return "", "", fmt.Errorf("error creating permission for S3 credentials: %w", err)
+	}
+
+	serviceAccount, err := conn.CreateServiceAccount(&ServiceAccount{
+		Name:        name,
+		Description: "Temporary S3 credentials of the Terraform provider, deleted when it shuts down",
+		Permissions: []string{permission.ID},
+	})
+	if err != nil {
+		deleteBootstrapCredentials(conn, "", permission.ID)
+		return "", "", fmt.Errorf("error creating service account for S3 credentials: %w", err)
+	}
+
+	registerShutdownHook(func() {
+		deleteBootstrapCredentials(conn, serviceAccount.ID, permission.ID)
+	})
+
+	log.Printf("[INFO] Created service account (%s) for S3 credentials", serviceAccount.ID)
+
+	err = verifyServiceAccountCredentials(ctx, conn, region, endpoint, serviceAccount.Accesskey, serviceAccount.Secret, []string{permission.ID}, bootstrapCredentialsTimeout)
+	if err != nil {
+		return "", "", fmt.Errorf("error verifying S3 credentials of service account (%s): %w", serviceAccount.ID, err)
+	}
+
+	return serviceAccount.Accesskey, serviceAccount.Secret, nil
+}
+
+// deleteBootstrapCredentials deletes a bootstrap service account, then its permission. Empty IDs are skipped.
+// Errors are only logged, what isn't deleted is deleted by a later run.
+func deleteBootstrapCredentials(conn *AuthData, serviceAccountId, permissionId string) {
+	if serviceAccountId != "" {
+		if _, err := conn.DeleteServiceAccount(serviceAccountId); err != nil && err.Error() != ServiceAccountNotFound {
+			log.Printf("[WARN] Unable to delete service account (%s) of S3 credentials: %s", serviceAccountId, err)
+			return
+		}
+	}
+
+	if permissionId != "" {
+		if _, err := conn.DeletePermission(permissionId); err != nil && err.Error() != PermissionNotFound {
+			log.Printf("[WARN] Unable to delete permission (%s) of S3 credentials: %s", permissionId, err)
+		}
+	}
+}
+
+// deleteLeftoverBootstrapCredentials deletes the bootstrap service accounts and permissions created more than
+// bootstrapLeftoverAge before now, i.e. left by runs killed before cleaning up. Errors are only logged.
+func deleteLeftoverBootstrapCredentials(conn *AuthData, now time.Time) {
+	cutoff := now.Add(-bootstrapLeftoverAge)
+
+	// service accounts first, as a permission attached to one can't be deleted.
+	serviceAccounts, err := conn.ListServiceAccounts()
+	if err != nil {
+		log.Printf("[WARN] Unable to list service accounts of S3 credentials left by previous runs: %s", err)
+		return
+	}
+
+	for _, serviceAccount := range serviceAccounts {
+		if bootstrapLeftover(serviceAccount.Name, cutoff) {
+			log.Printf("[DEBUG] Deleting service account (%s) of S3 credentials left by a previous run", serviceAccount.Id)
+			deleteBootstrapCredentials(conn, serviceAccount.Id, "")
+		}
+	}
+
+	permissions, err := conn.ListPermissions()
+	if err != nil {
+		log.Printf("[WARN] Unable to list permissions of S3 credentials left by previous runs: %s", err)
+		return
+	}
+
+	for _, permission := range permissions {
+		if bootstrapLeftover(permission.Name, cutoff) {
+			log.Printf("[DEBUG] Deleting permission (%s) of S3 credentials left by a previous run", permission.Id)
+			deleteBootstrapCredentials(conn, "", permission.Id)
+		}
+	}
+}
+
+// bootstrapLeftover returns true if name is the name of a bootstrap service account or permission created before cutoff.
+func bootstrapLeftover(name string, cutoff time.Time) bool {
+	if !strings.HasPrefix(name, bootstrapNamePrefix) || len(name) < len(bootstrapNamePrefix)+len(bootstrapTimeLayout) {
+		return false
+	}
+
+	createdAt, err := time.Parse(bootstrapTimeLayout, name[len(bootstrapNamePrefix):len(bootstrapNamePrefix)+len(bootstrapTimeLayout)])
+	if err != nil {
+		return false
+	}
+
+	return createdAt.Before(cutoff)
+}
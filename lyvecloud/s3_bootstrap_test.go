@@ -0,0 +1,169 @@
+package lyvecloud
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestProviderFromAccountAPI(t *testing.T) {
+	testCases := []struct {
+		Name          string
+		S3            map[string]interface{}
+		ExpectedError string
+	}{
+		{
+			Name: "buckets",
+			S3:   map[string]interface{}{"from_account_api": true, "from_account_api_buckets": []interface{}{"my-bucket"}},
+		},
+		{
+			Name: "bucket prefix",
+			S3:   map[string]interface{}{"from_account_api": true, "from_account_api_bucket_prefix": "my-"},
+		},
+		{
+			Name:          "unscoped",
+			S3:            map[string]interface{}{"from_account_api": true},
+			ExpectedError: "from_account_api requires from_account_api_buckets or from_account_api_bucket_prefix",
+		},
+		{
+			Name:          "scope without from_account_api",
+			S3:            map[string]interface{}{"access_key": "access-key", "secret_key": "secret-key", "from_account_api_bucket_prefix": "my-"},
+			ExpectedError: "can only be set with from_account_api",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			// the S3 client can't load a custom CA bundle into the transport of the fake Account API.
+			t.Setenv("AWS_CA_BUNDLE", "")
+			api := newFakeAccountAPI(t, nil)
+
+			s3 := map[string]interface{}{"region": "us-east-1", "endpoint_url": "s3.us-east-1.lyvecloud.seagate.com"}
+			for k, v := range testCase.S3 {
+				s3[k] = v
+			}
+
+			raw := map[string]interface{}{
+				"s3":      []interface{}{s3},
+				"account": []interface{}{map[string]interface{}{"token": "token"}},
+			}
+
+			provider := Provider()
+			err := diagError(provider.Configure(context.Background(), terraform.NewResourceConfigRaw(raw)))
+			if testCase.ExpectedError == "" && err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if testCase.ExpectedError != "" && (err == nil || !strings.Contains(err.Error(), testCase.ExpectedError)) {
+				t.Fatalf("got error %v, expected it to contain %q", err, testCase.ExpectedError)
+			}
+
+			// the credentials are only created on the first S3 API call.
+			if len(api.requests) > 0 {
+				t.Errorf("got Account API requests %v while configuring the provider, expected none", api.requests)
+			}
+		})
+	}
+}
+
+func TestBootstrapS3CredentialsCleanup(t *testing.T) {
+	var created Permission
+	api := newFakeAccountAPI(t, map[string]http.HandlerFunc{
+		"GET /v2/service-accounts": jsonHandler(http.StatusOK, []GetServiceAccountResponse{}),
+		"GET /v2/permissions":      jsonHandler(http.StatusOK, []GetPermissionResponse{}),
+		"POST /v2/permissions": func(w http.ResponseWriter, r *http.Request) {
+			if err := json.NewDecoder(r.Body).Decode(&created); err != nil {
+				t.Errorf("error decoding permission: %s", err)
+			}
+			jsonHandler(http.StatusOK, PermissionResponse{ID: "permission-id"})(w, r)
+		},
+		"POST /v2/service-accounts":            jsonHandler(http.StatusBadRequest, map[string]string{"code": "InvalidRequest"}),
+		"DELETE /v2/permissions/permission-id": jsonHandler(http.StatusOK, nil),
+	})
+
+	scope := &Permission{Type: "bucket-prefix", Prefix: "my-"}
+
+	_, _, err := bootstrapS3Credentials(context.Background(), &AuthData{}, "us-east-1", "endpoint", scope)
+	if err == nil || !strings.Contains(err.Error(), "InvalidRequest") {
+		t.Fatalf("got error %v, expected InvalidRequest", err)
+	}
+
+	if created.Type != "bucket-prefix" || created.Prefix != "my-" {
+		t.Errorf("got permission %+v, expected a bucket-prefix permission on my-", created)
+	}
+
+	// the name of the permission carries its creation time, compared to the cutoff of leftovers.
+	if bootstrapLeftover(created.Name, time.Now().Add(-time.Minute)) {
+		t.Errorf("got permission %s as created before a cutoff preceding its creation", created.Name)
+	}
+	if !bootstrapLeftover(created.Name, time.Now().Add(time.Minute)) {
+		t.Errorf("got permission %s as not created before a cutoff following its creation", created.Name)
+	}
+
+	if got := api.count("DELETE /v2/permissions/permission-id"); got != 1 {
+		t.Errorf("got %d deletions of the permission, expected 1", got)
+	}
+}
+
+func TestDeleteLeftoverBootstrapCredentials(t *testing.T) {
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	old := bootstrapNamePrefix + now.Add(-bootstrapLeftoverAge-time.Hour).Format(bootstrapTimeLayout) + "000000000001"
+	recent := bootstrapNamePrefix + now.Add(-time.Hour).Format(bootstrapTimeLayout) + "000000000002"
+
+	api := newFakeAccountAPI(t, map[string]http.HandlerFunc{
+		"GET /v2/service-accounts": jsonHandler(http.StatusOK, []GetServiceAccountResponse{
+			{Id: "old-sa", Name: old},
+			{Id: "recent-sa", Name: recent},
+			{Id: "other-sa", Name: "sa"},
+		}),
+		"GET /v2/permissions": jsonHandler(http.StatusOK, []GetPermissionResponse{
+			{Id: "old-permission", Name: old},
+			{Id: "orphan-permission", Name: old},
+			{Id: "recent-permission", Name: recent},
+			{Id: "other-permission", Name: bootstrapNamePrefix + "other"},
+		}),
+		"DELETE /v2/service-accounts/old-sa":       jsonHandler(http.StatusOK, nil),
+		"DELETE /v2/permissions/old-permission":    jsonHandler(http.StatusOK, nil),
+		"DELETE /v2/permissions/orphan-permission": jsonHandler(http.StatusOK, nil),
+	})
+
+	deleteLeftoverBootstrapCredentials(&AuthData{}, now)
+
+	expected := []string{
+		"GET /v2/service-accounts",
+		"DELETE /v2/service-accounts/old-sa",
+		"GET /v2/permissions",
+		"DELETE /v2/permissions/old-permission",
+		"DELETE /v2/permissions/orphan-permission",
+	}
+	if !reflect.DeepEqual(api.requests, expected) {
+		t.Errorf("got requests %v, expected %v", api.requests, expected)
+	}
+}
+
+func TestShutdownTimeout(t *testing.T) {
+	t.Cleanup(func() {
+		shutdownHooksMu.Lock()
+		shutdownHooks = nil
+		shutdownHooksMu.Unlock()
+	})
+
+	release := make(chan struct{})
+	defer close(release)
+
+	registerShutdownHook(func() {
+		<-release
+	})
+
+	start := time.Now()
+	Shutdown()
+
+	if elapsed := time.Since(start); elapsed > 2*shutdownTimeout {
+		t.Errorf("got Shutdown returning after %s, expected it to give up after %s", elapsed, shutdownTimeout)
+	}
+}
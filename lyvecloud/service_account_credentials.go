@@ -0,0 +1,106 @@
+package lyvecloud
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"regexp"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/hashicorp/aws-sdk-go-base/v2/awsv1shim/v2/tfawserr"
+)
+
+// serviceAccountCreateTimeout is the default create timeout of service accounts, bounding the wait for verify_credentials.
+const serviceAccountCreateTimeout = 5 * time.Minute
+
+// verifyServiceAccountCredentials waits until the keys of a new service account are accepted by the S3 API
+// at the given endpoint and region. The first bucket granted by name or prefix is checked with HeadBucket,
+// otherwise the buckets are listed.
+func verifyServiceAccountCredentials(ctx context.Context, accountConn *AuthData, region, endpoint, accessKey, secret string, permissionIds []string, timeout time.Duration) error {
+	conn, err := createS3Client(region, accessKey, secret, endpoint)
+	if err != nil {
+		return err
+	}
+
+	bucket, err := firstPermittedBucket(accountConn, permissionIds)
+	if err != nil {
+		return err
+	}
+
+	_, err = RetryWhenContext(ctx, timeout, func() (interface{}, error) {
+		if bucket != "" {
+			return conn.HeadBucketWithContext(ctx, &s3.HeadBucketInput{
+				Bucket: aws.String(bucket),
+			})
+		}
+
+		return conn.ListBucketsWithContext(ctx, &s3.ListBucketsInput{})
+	}, serviceAccountCredentialsRetryable(bucket != ""))
+
+	return err
+}
+
+// serviceAccountCredentialsRetryable retries the errors returned while new keys are not accepted yet.
+// With headBucket, a missing bucket still means the keys were accepted.
+func serviceAccountCredentialsRetryable(headBucket bool) Retryable {
+	return func(err error) (bool, error) {
+		if err == nil || (headBucket && tfawserr.ErrStatusCodeEquals(err, http.StatusNotFound)) {
+			return false, nil
+		}
+
+		if tfawserr.ErrCodeEquals(err, "InvalidAccessKeyId", "SignatureDoesNotMatch", "AccessDenied") || tfawserr.ErrStatusCodeEquals(err, http.StatusForbidden) {
+			log.Printf("[DEBUG] Service account credentials are not accepted yet: %s", err)
+			return true, err
+		}
+
+		return false, err
+	}
+}
+
+// firstPermittedBucket returns the first bucket granted by name or by prefix by the given permissions, or "" if there is none.
+// For a prefix, a valid bucket name starting with the prefix is granted whether it exists or not. Prefixes no valid bucket
+// name can start with are skipped, as HeadBucket would fail on the name rather than on the keys.
+func firstPermittedBucket(conn *AuthData, permissionIds []string) (string, error) {
+	for _, id := range permissionIds {
+		permission, err := conn.GetPermission(id)
+		if err != nil {
+			return "", fmt.Errorf("error reading permission (%s): %w", id, err)
+		}
+
+		if permission.Type == "bucket-names" && len(permission.Buckets) > 0 {
+			return permission.Buckets[0], nil
+		}
+
+		if permission.Type == "bucket-prefix" && permission.Prefix != "" {
+			if bucket := bucketNameWithPrefix(permission.Prefix); bucket != "" {
+				return bucket, nil
+			}
+			log.Printf("[DEBUG] No valid bucket name starts with the prefix (%s) of permission (%s)", permission.Prefix, id)
+		}
+	}
+
+	return "", nil
+}
+
+// bucketNameRegexp matches the bucket names accepted by the S3 API.
+var bucketNameRegexp = regexp.MustCompile(`^[a-z0-9][a-z0-9.\-]{1,61}[a-z0-9]$`)
+
+// bucketNameWithPrefix returns a valid bucket name starting with prefix, padded with "0" to end with a letter or digit
+// and to the minimum length of bucket names, or "" if there is none.
+func bucketNameWithPrefix(prefix string) string {
+	bucket := prefix
+	if last := bucket[len(bucket)-1]; !(last >= 'a' && last <= 'z' || last >= '0' && last <= '9') {
+		bucket += "0"
+	}
+	for len(bucket) < 3 {
+		bucket += "0"
+	}
+
+	if !bucketNameRegexp.MatchString(bucket) {
+		return ""
+	}
+	return bucket
+}
@@ -0,0 +1,185 @@
+package lyvecloud
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+)
+
+func TestServiceAccountCredentialsRetryable(t *testing.T) {
+	testCases := []struct {
+		Name        string
+		HeadBucket  bool
+		Err         error
+		Retry       bool
+		ExpectError bool
+	}{
+		{
+			Name: "accepted",
+		},
+		{
+			Name:  "unknown access key",
+			Err:   awserr.New("InvalidAccessKeyId", "The Access Key Id you provided does not exist in our records.", nil),
+			Retry: true,
+		},
+		{
+			Name:  "forbidden head bucket",
+			Err:   awserr.NewRequestFailure(awserr.New("Forbidden", "Forbidden", nil), http.StatusForbidden, "request-id"),
+			Retry: true,
+		},
+		{
+			Name:       "missing bucket",
+			HeadBucket: true,
+			Err:        awserr.NewRequestFailure(awserr.New("NotFound", "Not Found", nil), http.StatusNotFound, "request-id"),
+		},
+		{
+			Name:        "missing bucket when listing",
+			Err:         awserr.NewRequestFailure(awserr.New("NotFound", "Not Found", nil), http.StatusNotFound, "request-id"),
+			ExpectError: true,
+		},
+		{
+			Name:        "other error",
+			Err:         errors.New("connection refused"),
+			ExpectError: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			retry, err := serviceAccountCredentialsRetryable(testCase.HeadBucket)(testCase.Err)
+
+			if retry != testCase.Retry {
+				t.Errorf("got retry %t, expected %t", retry, testCase.Retry)
+			}
+
+			if !testCase.Retry {
+				if testCase.ExpectError && err == nil {
+					t.Fatal("expected error")
+				} else if !testCase.ExpectError && err != nil {
+					t.Fatalf("unexpected error: %s", err)
+				}
+			}
+		})
+	}
+}
+
+func TestBucketNameWithPrefix(t *testing.T) {
+	testCases := []struct {
+		Prefix   string
+		Expected string
+	}{
+		{Prefix: "logs", Expected: "logs"},
+		{Prefix: "a", Expected: "a00"},
+		{Prefix: "logs-", Expected: "logs-0"},
+		{Prefix: "logs.", Expected: "logs.0"},
+		{Prefix: "logs_", Expected: ""},
+		{Prefix: "Logs", Expected: ""},
+		{Prefix: "-logs", Expected: ""},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Prefix, func(t *testing.T) {
+			if got := bucketNameWithPrefix(testCase.Prefix); got != testCase.Expected {
+				t.Errorf("got %q, expected %q", got, testCase.Expected)
+			}
+		})
+	}
+}
+
+func TestVerifyServiceAccountCredentials(t *testing.T) {
+	testCases := []struct {
+		Name          string
+		Permission    GetPermissionResponse
+		Rejections    int
+		Status        int
+		ExpectedCall  string
+		ExpectedError string
+	}{
+		{
+			Name:         "bucket names",
+			Permission:   GetPermissionResponse{Type: "bucket-names", Buckets: []string{"logs", "backup"}},
+			Status:       http.StatusOK,
+			ExpectedCall: "HEAD /logs",
+		},
+		{
+			Name:         "missing bucket",
+			Permission:   GetPermissionResponse{Type: "bucket-names", Buckets: []string{"logs"}},
+			Status:       http.StatusNotFound,
+			ExpectedCall: "HEAD /logs",
+		},
+		{
+			Name:         "bucket prefix",
+			Permission:   GetPermissionResponse{Type: "bucket-prefix", Prefix: "logs-"},
+			Status:       http.StatusNotFound,
+			ExpectedCall: "HEAD /logs-0",
+		},
+		{
+			Name:         "bucket prefix with no valid bucket name",
+			Permission:   GetPermissionResponse{Type: "bucket-prefix", Prefix: "logs_"},
+			Status:       http.StatusOK,
+			ExpectedCall: "GET /",
+		},
+		{
+			Name:         "all buckets",
+			Permission:   GetPermissionResponse{Type: "all-buckets"},
+			Status:       http.StatusOK,
+			ExpectedCall: "GET /",
+		},
+		{
+			Name:         "keys accepted after a rejection",
+			Permission:   GetPermissionResponse{Type: "bucket-names", Buckets: []string{"logs"}},
+			Rejections:   1,
+			Status:       http.StatusOK,
+			ExpectedCall: "HEAD /logs",
+		},
+		{
+			Name:          "other error",
+			Permission:    GetPermissionResponse{Type: "bucket-names", Buckets: []string{"logs"}},
+			Status:        http.StatusBadRequest,
+			ExpectedCall:  "HEAD /logs",
+			ExpectedError: "400",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			// the S3 client can't load a custom CA bundle into the transport of the fake API.
+			t.Setenv("AWS_CA_BUNDLE", "")
+
+			rejections := testCase.Rejections
+			s3Handler := func(w http.ResponseWriter, r *http.Request) {
+				if rejections > 0 {
+					rejections--
+					w.WriteHeader(http.StatusForbidden)
+					return
+				}
+				w.WriteHeader(testCase.Status)
+				if r.Method == http.MethodGet {
+					w.Write([]byte(`<ListAllMyBucketsResult><Buckets></Buckets></ListAllMyBucketsResult>`))
+				}
+			}
+
+			api := newFakeAccountAPI(t, map[string]http.HandlerFunc{
+				"GET /v2/permissions/permission-id": jsonHandler(http.StatusOK, testCase.Permission),
+				testCase.ExpectedCall:               s3Handler,
+			})
+
+			err := verifyServiceAccountCredentials(context.Background(), &AuthData{}, "us-east-1", "s3.us-east-1.lyvecloud.seagate.com", "access-key", "secret", []string{"permission-id"}, time.Minute)
+			if testCase.ExpectedError == "" && err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if testCase.ExpectedError != "" && (err == nil || !strings.Contains(err.Error(), testCase.ExpectedError)) {
+				t.Fatalf("got error %v, expected it to contain %q", err, testCase.ExpectedError)
+			}
+
+			if got, expected := api.count(testCase.ExpectedCall), testCase.Rejections+1; got != expected {
+				t.Errorf("got %d calls to %s, expected %d", got, testCase.ExpectedCall, expected)
+			}
+		})
+	}
+}
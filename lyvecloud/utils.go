@@ -1,6 +1,10 @@
 package lyvecloud
 
-import "github.com/aws/aws-sdk-go/aws"
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+)
 
 // CheckCredentials checks that the client being used for the calling resource is nil, which is caused by missing credentials.
 func CheckCredentials(cType string, client Client) bool {
@@ -21,6 +25,15 @@ func CheckCredentials(cType string, client Client) bool {
 	return true
 }
 
+// CheckReadOnly returns an error if the provider is read-only, in which case action must not be made.
+func CheckReadOnly(client Client, action string) error {
+	if client.ReadOnly {
+		return fmt.Errorf("unable to %s: the provider is read-only (read_only = true)", action)
+	}
+
+	return nil
+}
+
 // Expands a map of string to interface to a map of string to *string
 func ExpandStringMap(m map[string]interface{}) map[string]*string {
 	stringMap := make(map[string]*string, len(m))
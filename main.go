@@ -13,4 +13,7 @@ func main() {
 			return lyvecloud.Provider()
 		},
 	})
+
+	// Serve returns once Terraform stops the plugin.
+	lyvecloud.Shutdown()
 }